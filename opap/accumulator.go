@@ -0,0 +1,129 @@
+package opap
+
+import (
+	"sort"
+	"sync"
+)
+
+// DrawAccumulator collects draws from multiple goroutines. It is safe for
+// concurrent use and its zero value is ready to use.
+type DrawAccumulator struct {
+	mu    sync.Mutex
+	draws []Draw
+	seen  map[int]struct{}
+}
+
+// Add appends draws to the accumulator.
+func (a *DrawAccumulator) Add(d ...Draw) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.draws = append(a.draws, d...)
+	if a.seen != nil {
+		for _, dr := range d {
+			a.seen[dr.DrawNo] = struct{}{}
+		}
+	}
+}
+
+// AddUnique appends the draws whose DrawNo has not been added before, for
+// example to keep a draw close to midnight once when it is returned for two
+// consecutive days. Of draws with the same DrawNo, the first one added is
+// kept.
+func (a *DrawAccumulator) AddUnique(d ...Draw) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.seen == nil {
+		a.seen = drawNumbers(len(a.draws), func(i int) int { return a.draws[i].DrawNo })
+	}
+	for _, dr := range d {
+		if _, ok := a.seen[dr.DrawNo]; !ok {
+			a.seen[dr.DrawNo] = struct{}{}
+			a.draws = append(a.draws, dr)
+		}
+	}
+}
+
+// Slice returns a snapshot of the accumulated draws sorted by DrawNo. Draws
+// with the same DrawNo keep the order they were added in.
+func (a *DrawAccumulator) Slice() []Draw {
+	a.mu.Lock()
+	draws := make([]Draw, len(a.draws))
+	copy(draws, a.draws)
+	a.mu.Unlock()
+
+	sort.SliceStable(draws, func(i, j int) bool { return draws[i].DrawNo < draws[j].DrawNo })
+	return draws
+}
+
+// Len returns the number of accumulated draws.
+func (a *DrawAccumulator) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.draws)
+}
+
+// PropoDrawAccumulator is like DrawAccumulator for the draws of the Propo
+// games.
+type PropoDrawAccumulator struct {
+	mu    sync.Mutex
+	draws []PropoDraw
+	seen  map[int]struct{}
+}
+
+// Add appends draws to the accumulator.
+func (a *PropoDrawAccumulator) Add(d ...PropoDraw) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.draws = append(a.draws, d...)
+	if a.seen != nil {
+		for _, dr := range d {
+			a.seen[dr.DrawNo] = struct{}{}
+		}
+	}
+}
+
+// AddUnique appends the draws whose DrawNo has not been added before. Of
+// draws with the same DrawNo, the first one added is kept.
+func (a *PropoDrawAccumulator) AddUnique(d ...PropoDraw) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.seen == nil {
+		a.seen = drawNumbers(len(a.draws), func(i int) int { return a.draws[i].DrawNo })
+	}
+	for _, dr := range d {
+		if _, ok := a.seen[dr.DrawNo]; !ok {
+			a.seen[dr.DrawNo] = struct{}{}
+			a.draws = append(a.draws, dr)
+		}
+	}
+}
+
+// Slice returns a snapshot of the accumulated draws sorted by DrawNo. Draws
+// with the same DrawNo keep the order they were added in.
+func (a *PropoDrawAccumulator) Slice() []PropoDraw {
+	a.mu.Lock()
+	draws := make([]PropoDraw, len(a.draws))
+	copy(draws, a.draws)
+	a.mu.Unlock()
+
+	sort.SliceStable(draws, func(i, j int) bool { return draws[i].DrawNo < draws[j].DrawNo })
+	return draws
+}
+
+// Len returns the number of accumulated draws.
+func (a *PropoDrawAccumulator) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.draws)
+}
+
+// drawNumbers returns the set of the numbers of n draws, where drawNo returns
+// the number of the i-th draw. AddUnique builds its set with it on first use,
+// so that accumulators that only Add do not keep one.
+func drawNumbers(n int, drawNo func(i int) int) map[int]struct{} {
+	seen := make(map[int]struct{}, n)
+	for i := 0; i < n; i++ {
+		seen[drawNo(i)] = struct{}{}
+	}
+	return seen
+}
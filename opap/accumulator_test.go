@@ -0,0 +1,86 @@
+package opap
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestDrawAccumulator(t *testing.T) {
+	var a DrawAccumulator
+
+	var wg sync.WaitGroup
+	for i := 10; i > 0; i-- {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			a.Add(Draw{DrawNo: n}, Draw{DrawNo: n + 10})
+		}(i)
+	}
+	wg.Wait()
+
+	if got, want := a.Len(), 20; got != want {
+		t.Errorf("DrawAccumulator.Len() = %d, want %d", got, want)
+	}
+
+	var want []Draw
+	for i := 1; i <= 20; i++ {
+		want = append(want, Draw{DrawNo: i})
+	}
+	if got := a.Slice(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DrawAccumulator.Slice() \nhave: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestDrawAccumulator_sliceIsSnapshot(t *testing.T) {
+	var a DrawAccumulator
+	a.Add(Draw{DrawNo: 2}, Draw{DrawNo: 1})
+
+	s := a.Slice()
+	a.Add(Draw{DrawNo: 3})
+	s[0].DrawNo = 100
+
+	want := []Draw{{DrawNo: 1}, {DrawNo: 2}, {DrawNo: 3}}
+	if got := a.Slice(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DrawAccumulator.Slice() \nhave: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestDrawAccumulator_AddUnique(t *testing.T) {
+	var a DrawAccumulator
+	a.Add(Draw{DrawNo: 2, DrawTime: "first"})
+	a.AddUnique(Draw{DrawNo: 2, DrawTime: "second"}, Draw{DrawNo: 1}, Draw{DrawNo: 1, DrawTime: "again"})
+	a.Add(Draw{DrawNo: 3})
+	a.AddUnique(Draw{DrawNo: 3, DrawTime: "again"})
+
+	want := []Draw{{DrawNo: 1}, {DrawNo: 2, DrawTime: "first"}, {DrawNo: 3}}
+	if got := a.Slice(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DrawAccumulator.Slice() \nhave: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestPropoDrawAccumulator(t *testing.T) {
+	var a PropoDrawAccumulator
+
+	var wg sync.WaitGroup
+	for i := 10; i > 0; i-- {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			a.AddUnique(PropoDraw{DrawNo: n}, PropoDraw{DrawNo: n % 5})
+		}(i)
+	}
+	wg.Wait()
+
+	if got, want := a.Len(), 11; got != want {
+		t.Errorf("PropoDrawAccumulator.Len() = %d, want %d", got, want)
+	}
+
+	var want []PropoDraw
+	for i := 0; i <= 10; i++ {
+		want = append(want, PropoDraw{DrawNo: i})
+	}
+	if got := a.Slice(); !reflect.DeepEqual(got, want) {
+		t.Errorf("PropoDrawAccumulator.Slice() \nhave: %#v\nwant: %#v", got, want)
+	}
+}
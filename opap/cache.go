@@ -0,0 +1,261 @@
+package opap
+
+import (
+	"bytes"
+	"container/list"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lru is a map of keys to values that remembers the order in which its keys
+// were used, so that the least recently used one can be evicted. It is not
+// safe for concurrent use and its zero value is empty.
+type lru struct {
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+// get returns the value of key and marks key as the most recently used one.
+func (l *lru) get(key string) (interface{}, bool) {
+	e, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	l.ll.MoveToFront(e)
+	return e.Value.(*lruEntry).value, true
+}
+
+// set sets the value of key and marks key as the most recently used one. If
+// more than maxEntries keys are then held, the least recently used one is
+// evicted. A maxEntries of zero or less means that the keys are not bounded.
+func (l *lru) set(key string, value interface{}, maxEntries int) {
+	if l.items == nil {
+		l.ll = list.New()
+		l.items = make(map[string]*list.Element)
+	}
+	if e, ok := l.items[key]; ok {
+		l.ll.MoveToFront(e)
+		e.Value.(*lruEntry).value = value
+		return
+	}
+	l.items[key] = l.ll.PushFront(&lruEntry{key: key, value: value})
+	if maxEntries > 0 && l.ll.Len() > maxEntries {
+		l.remove(l.ll.Back().Value.(*lruEntry).key)
+	}
+}
+
+// remove removes key, if held.
+func (l *lru) remove(key string) {
+	if e, ok := l.items[key]; ok {
+		l.ll.Remove(e)
+		delete(l.items, key)
+	}
+}
+
+// len returns the number of keys held.
+func (l *lru) len() int {
+	if l.ll == nil {
+		return 0
+	}
+	return l.ll.Len()
+}
+
+// LRUDrawCache is a cache of draws that holds at most Capacity entries. When
+// it is full, setting a new entry evicts the least recently used one. A
+// Capacity of zero or less means that the cache is not bounded. It is safe
+// for concurrent use and its zero value is an empty, unbounded cache.
+type LRUDrawCache struct {
+	Capacity int
+
+	mu      sync.Mutex
+	entries lru
+}
+
+// NewLRUDrawCache returns an empty cache that holds at most capacity draws.
+func NewLRUDrawCache(capacity int) *LRUDrawCache {
+	return &LRUDrawCache{Capacity: capacity}
+}
+
+// Get returns the draw cached under key and marks it as the most recently
+// used entry. The boolean result reports whether the key was found.
+func (c *LRUDrawCache) Get(key string) (*Draw, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if v, ok := c.entries.get(key); ok {
+		return v.(*Draw), true
+	}
+	return nil, false
+}
+
+// Set caches draw d under key as the most recently used entry, evicting the
+// least recently used entry if the cache is full.
+func (c *LRUDrawCache) Set(key string, d *Draw) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries.set(key, d, c.Capacity)
+}
+
+// GetOrFetch returns the draw cached under key. On a miss it calls fetch and
+// caches the draw it returns, unless fetch fails. The cache is not locked
+// while fetch runs, so concurrent misses for the same key may each call
+// fetch.
+func (c *LRUDrawCache) GetOrFetch(key string, fetch func() (*Draw, error)) (*Draw, error) {
+	if d, ok := c.Get(key); ok {
+		return d, nil
+	}
+	d, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.Set(key, d)
+	return d, nil
+}
+
+// Len returns the number of cached draws.
+func (c *LRUDrawCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries.len()
+}
+
+// latestCacheTTL is the longest time that the responses of the latest draw
+// and of the draws of the current day are cached by WithCache, since a new
+// draw can take place at any time.
+const latestCacheTTL = 30 * time.Second
+
+// fromCacheHeader is the header that is set on the responses that
+// cacheTransport answers from the cache.
+const fromCacheHeader = "X-From-Cache"
+
+// cacheTransport is an http.RoundTripper that caches the successful responses
+// of GET requests by URL for ttl, or for at most latestCacheTTL for the
+// requests of the latest draw and of the draws of the current day. It holds
+// at most maxEntries responses and evicts the least recently used one when
+// full.
+type cacheTransport struct {
+	base       http.RoundTripper
+	maxEntries int
+	ttl        time.Duration
+
+	mu      sync.Mutex
+	entries lru
+}
+
+type cachedResponse struct {
+	url        string
+	expires    time.Time
+	status     string
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+func newCacheTransport(maxEntries int, ttl time.Duration) *cacheTransport {
+	return &cacheTransport{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != "GET" {
+		return t.base.RoundTrip(req)
+	}
+	key := req.URL.String()
+	if cr, ok := t.get(key); ok {
+		resp := cr.response(req)
+		resp.Header.Set(fromCacheHeader, "1")
+		return resp, nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	ttl := t.ttl
+	if changesOften(req.URL.Path) && ttl > latestCacheTTL {
+		ttl = latestCacheTTL
+	}
+	cr := &cachedResponse{
+		url:        key,
+		expires:    now().Add(ttl),
+		status:     resp.Status,
+		statusCode: resp.StatusCode,
+		header:     resp.Header,
+		body:       body,
+	}
+	t.set(cr)
+	return cr.response(req), nil
+}
+
+// changesOften reports whether the response of the request of path can
+// change at any time: the latest draw of a game, and the draws of the current
+// day, which are added to as the draws of the day take place.
+func changesOften(path string) bool {
+	return strings.HasSuffix(path, "/last.json") ||
+		strings.HasSuffix(path, "/drawDate/"+now().Format("02-01-2006")+".json")
+}
+
+// get returns the unexpired response cached for key and marks it as the most
+// recently used one.
+func (t *cacheTransport) get(key string) (*cachedResponse, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, ok := t.entries.get(key)
+	if !ok {
+		return nil, false
+	}
+	cr := v.(*cachedResponse)
+	if !now().Before(cr.expires) {
+		t.entries.remove(key)
+		return nil, false
+	}
+	return cr, true
+}
+
+// set caches cr as the most recently used response, evicting the least
+// recently used one if the cache is full.
+func (t *cacheTransport) set(cr *cachedResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.entries.set(cr.url, cr, t.maxEntries)
+}
+
+// response returns a new response to req with the cached status, headers and
+// body.
+func (cr *cachedResponse) response(req *http.Request) *http.Response {
+	header := make(http.Header, len(cr.header))
+	for k, v := range cr.header {
+		header[k] = append([]string(nil), v...)
+	}
+	return &http.Response{
+		Status:        cr.status,
+		StatusCode:    cr.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(cr.body)),
+		ContentLength: int64(len(cr.body)),
+		Request:       req,
+	}
+}
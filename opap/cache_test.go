@@ -0,0 +1,246 @@
+package opap
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUDrawCache(t *testing.T) {
+	c := NewLRUDrawCache(2)
+
+	c.Set("a", &Draw{DrawNo: 1})
+	c.Set("b", &Draw{DrawNo: 2})
+	// Using "a" makes "b" the least recently used entry.
+	if d, ok := c.Get("a"); !ok || d.DrawNo != 1 {
+		t.Fatalf("Get(%q) = %v, %v, want draw 1, true", "a", d, ok)
+	}
+	c.Set("c", &Draw{DrawNo: 3})
+
+	if got, want := c.Len(), 2; got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Get(%q) found entry, want it evicted", "b")
+	}
+	for key, no := range map[string]int{"a": 1, "c": 3} {
+		if d, ok := c.Get(key); !ok || d.DrawNo != no {
+			t.Errorf("Get(%q) = %v, %v, want draw %d, true", key, d, ok, no)
+		}
+	}
+}
+
+func TestLRUDrawCache_setExisting(t *testing.T) {
+	c := NewLRUDrawCache(2)
+
+	c.Set("a", &Draw{DrawNo: 1})
+	c.Set("b", &Draw{DrawNo: 2})
+	c.Set("a", &Draw{DrawNo: 10})
+	c.Set("c", &Draw{DrawNo: 3})
+
+	if d, ok := c.Get("a"); !ok || d.DrawNo != 10 {
+		t.Errorf("Get(%q) = %v, %v, want draw 10, true", "a", d, ok)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Get(%q) found entry, want it evicted", "b")
+	}
+}
+
+func TestLRUDrawCache_zeroValue(t *testing.T) {
+	var c LRUDrawCache
+
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Get(%q) on empty cache found entry", "a")
+	}
+	for i := 0; i < 100; i++ {
+		c.Set(string(rune('a'+i)), &Draw{DrawNo: i})
+	}
+	if got, want := c.Len(), 100; got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+}
+
+func TestLRUDrawCache_GetOrFetch(t *testing.T) {
+	c := NewLRUDrawCache(1)
+
+	calls := 0
+	fetch := func() (*Draw, error) {
+		calls++
+		return &Draw{DrawNo: 1873}, nil
+	}
+	for i := 0; i < 2; i++ {
+		d, err := c.GetOrFetch("joker/1873", fetch)
+		if err != nil {
+			t.Fatal("GetOrFetch returned err:", err)
+		}
+		if got, want := d.DrawNo, 1873; got != want {
+			t.Errorf("GetOrFetch DrawNo = %d, want %d", got, want)
+		}
+	}
+	if got, want := calls, 1; got != want {
+		t.Errorf("fetch called %d times, want %d", got, want)
+	}
+}
+
+func TestLRUDrawCache_GetOrFetch_error(t *testing.T) {
+	c := NewLRUDrawCache(1)
+
+	fetchErr := errors.New("something broke")
+	_, err := c.GetOrFetch("joker/1873", func() (*Draw, error) { return nil, fetchErr })
+	if err != fetchErr {
+		t.Errorf("GetOrFetch err = %v, want %v", err, fetchErr)
+	}
+	if got := c.Len(); got != 0 {
+		t.Errorf("Len() = %d after failed fetch, want 0", got)
+	}
+}
+
+func TestWithCache(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/1873.json", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("X-Request-Id", "abc123")
+		fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+	})
+
+	c := NewClient(WithBaseURL(client.BaseURL), WithCache(10, time.Hour))
+	for i := 0; i < 2; i++ {
+		d, resp, err := c.Draws.ByNumber(Joker, 1873)
+		if err != nil {
+			t.Fatal("ByNumber returned err:", err)
+		}
+		if got, want := d.DrawNo, 1873; got != want {
+			t.Errorf("ByNumber call %d DrawNo = %d, want %d", i+1, got, want)
+		}
+		if got, want := HeadersFrom(resp).XRequestID(), "abc123"; got != want {
+			t.Errorf("ByNumber call %d X-Request-Id = %q, want %q", i+1, got, want)
+		}
+	}
+	if got, want := atomic.LoadInt32(&calls), int32(1); got != want {
+		t.Errorf("server called %d times, want %d", got, want)
+	}
+}
+
+func TestWithCache_expiry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var latest, byNumber int32
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&latest, 1)
+		fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/1873.json", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&byNumber, 1)
+		fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+	})
+
+	orig := now
+	defer func() { now = orig }()
+	start := time.Date(2018, 1, 9, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return start }
+
+	c := NewClient(WithBaseURL(client.BaseURL), WithCache(10, time.Hour))
+	fetch := func() {
+		if _, _, err := c.Draws.Latest(Joker); err != nil {
+			t.Fatal("Latest returned err:", err)
+		}
+		if _, _, err := c.Draws.ByNumber(Joker, 1873); err != nil {
+			t.Fatal("ByNumber returned err:", err)
+		}
+	}
+	fetch()
+	now = func() time.Time { return start.Add(latestCacheTTL - time.Second) }
+	fetch()
+	now = func() time.Time { return start.Add(latestCacheTTL) }
+	fetch()
+	now = func() time.Time { return start.Add(time.Hour) }
+	fetch()
+
+	if got, want := atomic.LoadInt32(&latest), int32(3); got != want {
+		t.Errorf("latest draw fetched %d times, want %d", got, want)
+	}
+	if got, want := atomic.LoadInt32(&byNumber), int32(2); got != want {
+		t.Errorf("draw by number fetched %d times, want %d", got, want)
+	}
+}
+
+func TestWithCache_currentDayExpiry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var today, yesterday int32
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/kino/drawDate/09-01-2018.json", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&today, 1)
+		fmt.Fprint(w, `{"draws":{"draw":[]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/kino/drawDate/08-01-2018.json", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&yesterday, 1)
+		fmt.Fprint(w, `{"draws":{"draw":[]}}`)
+	})
+
+	orig := now
+	defer func() { now = orig }()
+	start := time.Date(2018, 1, 9, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return start }
+
+	c := NewClient(WithBaseURL(client.BaseURL), WithCache(10, time.Hour))
+	fetch := func() {
+		for _, day := range []int{9, 8} {
+			if _, _, err := c.Draws.ByDate(Kino, day, 1, 2018); err != nil {
+				t.Fatal("ByDate returned err:", err)
+			}
+		}
+	}
+	fetch()
+	now = func() time.Time { return start.Add(latestCacheTTL) }
+	fetch()
+
+	if got, want := atomic.LoadInt32(&today), int32(2); got != want {
+		t.Errorf("draws of the current day fetched %d times, want %d", got, want)
+	}
+	if got, want := atomic.LoadInt32(&yesterday), int32(1); got != want {
+		t.Errorf("draws of the previous day fetched %d times, want %d", got, want)
+	}
+}
+
+func TestWithCache_errorsNotCached(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/1873.json", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "something broke", 500)
+	})
+
+	c := NewClient(WithBaseURL(client.BaseURL), WithCache(10, time.Hour))
+	for i := 0; i < 2; i++ {
+		_, _, err := c.Draws.ByNumber(Joker, 1873)
+		testErrorResponse(t, err, 500)
+	}
+	if got, want := atomic.LoadInt32(&calls), int32(2); got != want {
+		t.Errorf("server called %d times, want %d", got, want)
+	}
+}
+
+func TestCacheTransport_eviction(t *testing.T) {
+	ct := newCacheTransport(2, time.Hour)
+	for _, u := range []string{"a", "b", "c"} {
+		ct.set(&cachedResponse{url: u, expires: now().Add(time.Hour)})
+	}
+	if _, ok := ct.get("a"); ok {
+		t.Error("least recently used response was not evicted")
+	}
+	for _, u := range []string{"b", "c"} {
+		if _, ok := ct.get(u); !ok {
+			t.Errorf("response %q was evicted", u)
+		}
+	}
+}
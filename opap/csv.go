@@ -0,0 +1,145 @@
+package opap
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// MarshalCSV returns the CSV record of the draw which consists of the draw
+// time, the draw number and the results of the draw.
+func (d *Draw) MarshalCSV() ([]string, error) {
+	rec := make([]string, 0, 2+len(d.Results))
+	rec = append(rec, d.DrawTime, strconv.Itoa(d.DrawNo))
+	for _, n := range d.Results {
+		rec = append(rec, strconv.Itoa(n))
+	}
+	return rec, nil
+}
+
+// UnmarshalCSVDraw decodes a draw from a CSV record in the format of
+// Draw.MarshalCSV.
+func UnmarshalCSVDraw(record []string) (Draw, error) {
+	if len(record) < 2 {
+		return Draw{}, fmt.Errorf("draw CSV record has %d fields, want at least 2", len(record))
+	}
+	no, err := strconv.Atoi(record[1])
+	if err != nil {
+		return Draw{}, fmt.Errorf("invalid draw number %q: %v", record[1], err)
+	}
+	d := Draw{DrawTime: record[0], DrawNo: no}
+	if len(record) > 2 {
+		d.Results = make([]int, len(record)-2)
+		for i, f := range record[2:] {
+			n, err := strconv.Atoi(f)
+			if err != nil {
+				return Draw{}, fmt.Errorf("invalid result %q of draw %d: %v", f, no, err)
+			}
+			d.Results[i] = n
+		}
+	}
+	return d, nil
+}
+
+// WriteDrawsCSV writes draws to w as CSV records in the format of
+// Draw.MarshalCSV, one record per draw.
+func WriteDrawsCSV(w io.Writer, draws []Draw) error {
+	cw := csv.NewWriter(w)
+	for i := range draws {
+		rec, err := draws[i].MarshalCSV()
+		if err != nil {
+			return err
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadDrawsCSV reads draws from r as written by WriteDrawsCSV.
+func ReadDrawsCSV(r io.Reader) ([]Draw, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	var draws []Draw
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return draws, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		d, err := UnmarshalCSVDraw(rec)
+		if err != nil {
+			return nil, err
+		}
+		draws = append(draws, d)
+	}
+}
+
+// MarshalCSV returns the CSV record of the Propo draw which consists of the
+// draw time, the draw number and the results of the draw.
+func (d *PropoDraw) MarshalCSV() ([]string, error) {
+	rec := make([]string, 0, 2+len(d.Results))
+	rec = append(rec, d.DrawTime, strconv.Itoa(d.DrawNo))
+	rec = append(rec, d.Results...)
+	return rec, nil
+}
+
+// UnmarshalCSVPropoDraw decodes a Propo draw from a CSV record in the format
+// of PropoDraw.MarshalCSV.
+func UnmarshalCSVPropoDraw(record []string) (PropoDraw, error) {
+	if len(record) < 2 {
+		return PropoDraw{}, fmt.Errorf("propo draw CSV record has %d fields, want at least 2", len(record))
+	}
+	no, err := strconv.Atoi(record[1])
+	if err != nil {
+		return PropoDraw{}, fmt.Errorf("invalid draw number %q: %v", record[1], err)
+	}
+	d := PropoDraw{DrawTime: record[0], DrawNo: no}
+	if len(record) > 2 {
+		d.Results = make([]string, len(record)-2)
+		copy(d.Results, record[2:])
+	}
+	return d, nil
+}
+
+// WritePropoDrawsCSV is like WriteDrawsCSV for Propo draws.
+func WritePropoDrawsCSV(w io.Writer, draws []PropoDraw) error {
+	cw := csv.NewWriter(w)
+	for i := range draws {
+		rec, err := draws[i].MarshalCSV()
+		if err != nil {
+			return err
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadPropoDrawsCSV is like ReadDrawsCSV for Propo draws.
+func ReadPropoDrawsCSV(r io.Reader) ([]PropoDraw, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	var draws []PropoDraw
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return draws, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		d, err := UnmarshalCSVPropoDraw(rec)
+		if err != nil {
+			return nil, err
+		}
+		draws = append(draws, d)
+	}
+}
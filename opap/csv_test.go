@@ -0,0 +1,82 @@
+package opap
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDraw_MarshalCSV(t *testing.T) {
+	d := &Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+
+	got, err := d.MarshalCSV()
+	if err != nil {
+		t.Fatal("Draw.MarshalCSV returned err:", err)
+	}
+	want := []string{"24-12-2017T22:00:00", "1873", "40", "13", "1", "24", "15", "8"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Draw.MarshalCSV() \nhave: %q\nwant: %q", got, want)
+	}
+}
+
+func TestUnmarshalCSVDraw_errors(t *testing.T) {
+	for _, rec := range [][]string{
+		nil,
+		{"24-12-2017T22:00:00"},
+		{"24-12-2017T22:00:00", "foo"},
+		{"24-12-2017T22:00:00", "1873", "40", "foo"},
+	} {
+		if _, err := UnmarshalCSVDraw(rec); err == nil {
+			t.Errorf("UnmarshalCSVDraw(%q) expected error", rec)
+		}
+	}
+}
+
+func TestDrawsCSV_roundTrip(t *testing.T) {
+	draws := []Draw{
+		{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}},
+		{DrawTime: "28-12-2017T22:00:00", DrawNo: 1874},
+		{DrawTime: "24-12-2017T09:00:00", DrawNo: 639001, Results: []int{7}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDrawsCSV(&buf, draws); err != nil {
+		t.Fatal("WriteDrawsCSV returned err:", err)
+	}
+	got, err := ReadDrawsCSV(&buf)
+	if err != nil {
+		t.Fatal("ReadDrawsCSV returned err:", err)
+	}
+	if !reflect.DeepEqual(got, draws) {
+		t.Errorf("ReadDrawsCSV(WriteDrawsCSV(draws)) \nhave: %#v\nwant: %#v", got, draws)
+	}
+}
+
+func TestReadDrawsCSV_error(t *testing.T) {
+	if _, err := ReadDrawsCSV(bytes.NewBufferString("24-12-2017T22:00:00,foo\n")); err == nil {
+		t.Error("ReadDrawsCSV with invalid draw number expected error")
+	}
+}
+
+func TestPropoDrawsCSV_roundTrip(t *testing.T) {
+	draws := []PropoDraw{
+		{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751, Results: []string{"2", "2", "1", "X"}},
+		{DrawTime: "30-12-2017T16:00:00", DrawNo: 201752},
+	}
+
+	var buf bytes.Buffer
+	if err := WritePropoDrawsCSV(&buf, draws); err != nil {
+		t.Fatal("WritePropoDrawsCSV returned err:", err)
+	}
+	got, err := ReadPropoDrawsCSV(&buf)
+	if err != nil {
+		t.Fatal("ReadPropoDrawsCSV returned err:", err)
+	}
+	if !reflect.DeepEqual(got, draws) {
+		t.Errorf("ReadPropoDrawsCSV(WritePropoDrawsCSV(draws)) \nhave: %#v\nwant: %#v", got, draws)
+	}
+
+	if _, err := UnmarshalCSVPropoDraw([]string{"23-12-2017T16:00:00"}); err == nil {
+		t.Error("UnmarshalCSVPropoDraw with 1 field expected error")
+	}
+}
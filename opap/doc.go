@@ -16,26 +16,28 @@ Import the package using:
 
 First construct a new opap client:
 
-	c := opap.NewClient(nil)
+	c := opap.NewClient()
 
 The client supports three methods of returning draws for each game. It can
 return a specific draw by number or by date as well as the latest draw.  For
 example, to get the latest draw of the game Joker:
 
-	c := opap.NewClient(nil)
+	c := opap.NewClient()
+	ctx := context.Background()
 
 	draw, _, err := c.Draws.Latest(opap.Lotto)
 	// ...
 
-	draw, _, err := c.Draws.ByNumber(opap.Joker, 1873)
+	draw, _, err := c.Draws.ByNumberWithContext(ctx, opap.Joker, 1873)
 	// ...
 
-	draws, _, err := c.Draws.ByDate(opap.Kino, 27, 12, 2017)
+	draws, _, err := c.Draws.ByDateWithContext(ctx, opap.Kino, 27, 12, 2017)
 	// ...
 
-The Latest and ByNumber methods return one draw. The ByDate method returns a
-slice of Draw objects. Each Draw object contains the draw time, the draw number
-and the results as a slice of integers. It looks like this:
+The Latest and ByNumberWithContext methods return one draw. The
+ByDateWithContext method returns a slice of Draw objects. Each Draw object
+contains the draw time, the draw number and the results as a slice of integers.
+It looks like this:
 
 	opap.Draw{
 		DrawTime: "24-12-2017T22:00:00",
@@ -50,7 +52,7 @@ joker number.
 There are also three equivalent methods for the Propo games which return
 results as a slice of strings.
 
-	c := opap.NewClient(nil)
+	c := opap.NewClient()
 
 	draw, _, err := c.Draws.PropoLatest(opap.PropoWed)
 	// ...
@@ -61,8 +63,8 @@ results as a slice of strings.
 	draws, _, err := c.Draws.PropoByDate(opap.PropoSun, 17, 12, 2017)
 	// ...
 
-If you need more control, when creating a new client you can pass an
-http.Client as an argument.
+If you need more control, when creating a new client you can pass it options
+such as WithHTTPClient, WithBaseURL, WithDrawsEndpoint and WithUserAgent.
 
 For example this http.Client passed to the opap client will make sure to cancel
 any request that takes longer than 1 second:
@@ -70,7 +72,7 @@ any request that takes longer than 1 second:
 	httpcl := &http.Client{
 		Timeout: 1 * time.Second,
 	}
-	c := opap.NewClient(httpcl)
+	c := opap.NewClient(opap.WithHTTPClient(httpcl))
 	// ...
 
 Unit Testing
@@ -0,0 +1,1035 @@
+package opap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrEmptySlice is returned by functions that need at least one draw to
+// operate on when they are given none.
+var ErrEmptySlice = errors.New("empty slice of draws")
+
+// ErrInsufficientResults is returned by methods that need at least one
+// result of a draw to operate on when the draw has none.
+var ErrInsufficientResults = errors.New("insufficient draw results")
+
+// drawTimeLayout is the layout of the DrawTime field of the draws returned by
+// the OPAP REST service.
+const drawTimeLayout = "02-01-2006T15:04:05"
+
+// DrawTimeParser parses DrawTime values with layout Layout, as accepted by
+// time.Parse, in Location. It is meant for endpoints that format draw times
+// differently than the OPAP REST service does.
+type DrawTimeParser struct {
+	Layout string
+	// Location is the time zone of the draw times. If nil, UTC is used.
+	Location *time.Location
+}
+
+// athens is the time zone of the draw times of the OPAP REST service, which
+// reports them in Greek local time.
+var athens = loadAthens()
+
+// loadAthens loads the Europe/Athens time zone. If the time zone database is
+// not available, it falls back to Eastern European Time without daylight
+// saving time, which is off by an hour in the summer.
+func loadAthens() *time.Location {
+	loc, err := time.LoadLocation("Europe/Athens")
+	if err != nil {
+		return time.FixedZone("EET", 2*60*60)
+	}
+	return loc
+}
+
+// DefaultDrawTimeParser parses draw times as the OPAP REST service formats
+// them, in Greek local time (Europe/Athens). It is used by the methods and
+// functions of the package that parse draw times, such as Draw.Time, as well
+// as by the Client unless the WithDrawTimeParser option is given.
+var DefaultDrawTimeParser = DrawTimeParser{Layout: drawTimeLayout, Location: athens}
+
+// Parse parses the draw time s. Surrounding whitespace, which the service
+// occasionally responds with, is trimmed before parsing.
+func (p DrawTimeParser) Parse(s string) (time.Time, error) {
+	return time.ParseInLocation(p.Layout, strings.TrimSpace(s), p.location())
+}
+
+// location returns the Location of the parser, or UTC if it is nil.
+func (p DrawTimeParser) location() *time.Location {
+	if p.Location == nil {
+		return time.UTC
+	}
+	return p.Location
+}
+
+// parseDrawTime parses a DrawTime value with DefaultDrawTimeParser.
+func parseDrawTime(s string) (time.Time, error) {
+	return DefaultDrawTimeParser.Parse(s)
+}
+
+// Time parses the DrawTime of the draw with DefaultDrawTimeParser. The
+// service uses the layout "02-01-2006T15:04:05" with no time zone, in Greek
+// local time, so the time is returned in the Europe/Athens time zone. It
+// returns an error if DrawTime is malformed.
+func (d *Draw) Time() (time.Time, error) {
+	return parseDrawTime(d.DrawTime)
+}
+
+// Sum returns the sum of the results of the draw.
+func (d *Draw) Sum() int {
+	sum := 0
+	for _, n := range d.Results {
+		sum += n
+	}
+	return sum
+}
+
+// Max returns the largest result of the draw or 0 if it has no results.
+func (d *Draw) Max() int {
+	if len(d.Results) == 0 {
+		return 0
+	}
+	max := d.Results[0]
+	for _, n := range d.Results[1:] {
+		if n > max {
+			max = n
+		}
+	}
+	return max
+}
+
+// Min returns the smallest result of the draw or 0 if it has no results.
+func (d *Draw) Min() int {
+	if len(d.Results) == 0 {
+		return 0
+	}
+	min := d.Results[0]
+	for _, n := range d.Results[1:] {
+		if n < min {
+			min = n
+		}
+	}
+	return min
+}
+
+// Mean returns the average of the results of the draw or 0 if it has no
+// results.
+func (d *Draw) Mean() float64 {
+	if len(d.Results) == 0 {
+		return 0
+	}
+	return float64(d.Sum()) / float64(len(d.Results))
+}
+
+// SumNormalized returns the sum of the main results of the draw of game g,
+// that is the results without a joker or bonus number, scaled against the
+// smallest and the largest possible sum of g to a value in [0, 1], so that
+// sums can be compared across games. It returns ErrUnknownGame if g is not
+// one of the SupportedGames, ErrNoBallPool if g does not draw balls and
+// ErrInsufficientResults if the draw has no results.
+func (d *Draw) SumNormalized(g Game) (float64, error) {
+	info, err := InfoFor(g)
+	if err != nil {
+		return 0, err
+	}
+	if info.PoolSize == 0 {
+		return 0, ErrNoBallPool
+	}
+	if len(d.mainResults(g)) == 0 {
+		return 0, ErrInsufficientResults
+	}
+	min, max := info.sumRange()
+	return float64(d.mainSum(g)-min) / float64(max-min), nil
+}
+
+// StandardDeviation returns the population standard deviation of the main
+// results of the draw of game g, that is the results without a joker or bonus
+// number. It returns ErrUnknownGame if g is not one of the SupportedGames and
+// ErrInsufficientResults if the draw has no results.
+func (d *Draw) StandardDeviation(g Game) (float64, error) {
+	if _, err := InfoFor(g); err != nil {
+		return 0, err
+	}
+	main := d.mainResults(g)
+	if len(main) == 0 {
+		return 0, ErrInsufficientResults
+	}
+	var sum float64
+	for _, n := range main {
+		sum += float64(n)
+	}
+	mean := sum / float64(len(main))
+	var variance float64
+	for _, n := range main {
+		diff := float64(n) - mean
+		variance += diff * diff
+	}
+	return math.Sqrt(variance / float64(len(main))), nil
+}
+
+// Contains reports whether n is one of the results of the draw.
+func (d *Draw) Contains(n int) bool {
+	for _, r := range d.Results {
+		if r == n {
+			return true
+		}
+	}
+	return false
+}
+
+// JokerNumber returns the joker number of a Joker draw, which is its last
+// result. The boolean result is true only when the draw has the 6 results of
+// a Joker draw.
+func (d *Draw) JokerNumber() (int, bool) {
+	if !d.HasJokerBall(Joker) {
+		return 0, false
+	}
+	return d.Results[len(d.Results)-1], true
+}
+
+// GameDraw bundles a Draw together with the game it belongs to, since Draw
+// itself does not carry its game type.
+type GameDraw struct {
+	Game
+	Draw
+}
+
+// String returns the game, the number and the results of the draw. It keeps
+// the String method of the embedded Game from describing the whole GameDraw.
+func (gd GameDraw) String() string {
+	return fmt.Sprintf("%v draw %d %v", gd.Game, gd.DrawNo, gd.Results)
+}
+
+// gameDrawJSON is the JSON encoding of a GameDraw, which adds the game to the
+// fields of the draw.
+type gameDrawJSON struct {
+	Game string `json:"game"`
+	Draw
+}
+
+// MarshalJSON encodes the draw like Draw does with an added "game" field that
+// holds the name of the game, for example "joker".
+func (gd GameDraw) MarshalJSON() ([]byte, error) {
+	return json.Marshal(gameDrawJSON{Game: string(gd.Game), Draw: gd.Draw})
+}
+
+// UnmarshalJSON decodes a GameDraw encoded by MarshalJSON.
+func (gd *GameDraw) UnmarshalJSON(data []byte) error {
+	var v gameDrawJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	gd.Game, gd.Draw = Game(v.Game), v.Draw
+	return nil
+}
+
+// WithGame returns a GameDraw that bundles the draw with game g.
+func (d *Draw) WithGame(g Game) GameDraw {
+	return GameDraw{Game: g, Draw: *d}
+}
+
+// The methods below call the methods of Draw that take the game of the draw
+// with the embedded Game, so that callers of a GameDraw need not pass it.
+
+// StandardDeviation is like Draw.StandardDeviation for the game of the draw.
+func (gd GameDraw) StandardDeviation() (float64, error) {
+	return gd.Draw.StandardDeviation(gd.Game)
+}
+
+// SumNormalized is like Draw.SumNormalized for the game of the draw.
+func (gd GameDraw) SumNormalized() (float64, error) {
+	return gd.Draw.SumNormalized(gd.Game)
+}
+
+// InRange is like Draw.InRange for the game of the draw.
+func (gd GameDraw) InRange() (bool, error) {
+	return gd.Draw.InRange(gd.Game)
+}
+
+// SortedMainResults is like Draw.SortedMainResults for the game of the draw.
+func (gd GameDraw) SortedMainResults() ([]int, error) {
+	return gd.Draw.SortedMainResults(gd.Game)
+}
+
+// SortedBonusResults is like Draw.SortedBonusResults for the game of the
+// draw.
+func (gd GameDraw) SortedBonusResults() ([]int, error) {
+	return gd.Draw.SortedBonusResults(gd.Game)
+}
+
+// Mark is like Draw.Mark for the game of the draw.
+func (gd GameDraw) Mark() (MarkedDraw, error) {
+	return gd.Draw.Mark(gd.Game)
+}
+
+// FuzzyMatch is like Draw.FuzzyMatch for the game of the draw.
+func (gd GameDraw) FuzzyMatch(ticket []int, maxMisses int) (bool, error) {
+	return gd.Draw.FuzzyMatch(ticket, maxMisses, gd.Game)
+}
+
+// HasJokerBall is like Draw.HasJokerBall for the game of the draw.
+func (gd GameDraw) HasJokerBall() bool {
+	return gd.Draw.HasJokerBall(gd.Game)
+}
+
+// Summary is like Draw.Summary for the game of the draw.
+func (gd GameDraw) Summary() string {
+	return gd.Draw.Summary(gd.Game)
+}
+
+// ToInfluxLineProtocol is like Draw.ToInfluxLineProtocol for the game of the
+// draw.
+func (gd GameDraw) ToInfluxLineProtocol() (string, error) {
+	return gd.Draw.ToInfluxLineProtocol(gd.Game)
+}
+
+// HasDuplicates reports whether any two of the draw's results are equal,
+// which for games that draw distinct balls out of a single pool indicates
+// corrupt draw data. Digit games such as Proto and Super3, as well as the
+// bonus number of games like Joker which is drawn out of a separate pool, can
+// legitimately repeat a number.
+func (d *Draw) HasDuplicates() bool {
+	seen := make(map[int]struct{}, len(d.Results))
+	for _, n := range d.Results {
+		if _, ok := seen[n]; ok {
+			return true
+		}
+		seen[n] = struct{}{}
+	}
+	return false
+}
+
+// ToJSON returns the JSON encoding of the draw.
+func (d *Draw) ToJSON() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// ToJSONString returns the JSON encoding of the draw as a string.
+func (d *Draw) ToJSONString() (string, error) {
+	b, err := d.ToJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// MostRecentOf returns the draw with the highest DrawNo out of draws. When
+// more than one draw has the highest DrawNo, the one with the latest DrawTime
+// is returned. It returns ErrEmptySlice if draws is empty.
+func MostRecentOf(draws []Draw) (*Draw, error) {
+	if len(draws) == 0 {
+		return nil, ErrEmptySlice
+	}
+	latest := &draws[0]
+	for i := 1; i < len(draws); i++ {
+		d := &draws[i]
+		newer, err := isMoreRecent(d.DrawNo, d.DrawTime, latest.DrawNo, latest.DrawTime)
+		if err != nil {
+			return nil, err
+		}
+		if newer {
+			latest = d
+		}
+	}
+	return latest, nil
+}
+
+// isMoreRecent reports whether the draw with number no and time tm is more
+// recent than the draw with number otherNo and time otherTm. The times are
+// only parsed when the draw numbers are equal.
+func isMoreRecent(no int, tm string, otherNo int, otherTm string) (bool, error) {
+	if no != otherNo {
+		return no > otherNo, nil
+	}
+	t, err := parseDrawTime(tm)
+	if err != nil {
+		return false, err
+	}
+	other, err := parseDrawTime(otherTm)
+	if err != nil {
+		return false, err
+	}
+	return t.After(other), nil
+}
+
+// HasJokerBall reports whether the draw is a draw of game g that includes the
+// joker number, which is the sixth and last of the results of a Joker draw.
+// It guards against indexing the results of draws of other games or of draws
+// with missing results.
+func (d *Draw) HasJokerBall(g Game) bool {
+	return g == Joker && len(d.Results) == Joker.ResultCount()
+}
+
+// GroupDrawsByPattern buckets draws by the key that bucketFn returns for each
+// of them. The draws of each bucket keep their order in draws.
+func GroupDrawsByPattern(draws []Draw, bucketFn func(Draw) string) map[string][]Draw {
+	groups := make(map[string][]Draw)
+	for _, d := range draws {
+		k := bucketFn(d)
+		groups[k] = append(groups[k], d)
+	}
+	return groups
+}
+
+// BucketByConsecutiveRunLength is a bucket function for GroupDrawsByPattern
+// that keys a draw by the length of the longest run of consecutive numbers
+// among its results, for example "consecutive_run_3" for a Kino draw that
+// includes 17, 18 and 19. Draws without results are keyed by
+// "consecutive_run_0".
+func BucketByConsecutiveRunLength(d Draw) string {
+	return fmt.Sprintf("consecutive_run_%d", longestConsecutiveRun(d.Results))
+}
+
+// BucketBySumQuintile returns a bucket function for GroupDrawsByPattern that
+// keys a draw of game g by the fifth of the range of possible sums that the
+// sum of its main results falls in, as scaled by Draw.SumNormalized, from
+// "sum_quintile_1" for the lowest sums to "sum_quintile_5" for the highest.
+// Draws whose sum cannot be scaled, because they have no results or g is not
+// a game that draws balls, are keyed by "sum_quintile_0".
+func BucketBySumQuintile(g Game) func(Draw) string {
+	return func(d Draw) string {
+		sum, err := d.SumNormalized(g)
+		if err != nil {
+			return "sum_quintile_0"
+		}
+		q := int(sum*5) + 1
+		if q > 5 {
+			q = 5
+		}
+		return fmt.Sprintf("sum_quintile_%d", q)
+	}
+}
+
+// BucketByLowHighBalance returns a bucket function for GroupDrawsByPattern
+// that keys a draw of game g by how many of its main results fall in the
+// lower and the upper half of the pool of g, for example "low_3_high_2" for a
+// Joker draw with three main numbers up to 22 and two from 23 to 45. The
+// joker or bonus numbers are left out. Draws of games that do not draw balls
+// are keyed by "low_0_high_0".
+func BucketByLowHighBalance(g Game) func(Draw) string {
+	info, _ := InfoFor(g)
+	return func(d Draw) string {
+		low, high := 0, 0
+		if info.PoolSize > 0 {
+			for _, n := range d.mainResults(g) {
+				if n < info.FirstBall+info.PoolSize/2 {
+					low++
+				} else {
+					high++
+				}
+			}
+		}
+		return fmt.Sprintf("low_%d_high_%d", low, high)
+	}
+}
+
+func longestConsecutiveRun(results []int) int {
+	if len(results) == 0 {
+		return 0
+	}
+	sorted := make([]int, len(results))
+	copy(sorted, results)
+	sort.Ints(sorted)
+
+	longest, run := 1, 1
+	for i := 1; i < len(sorted); i++ {
+		switch sorted[i] - sorted[i-1] {
+		case 0:
+			continue
+		case 1:
+			run++
+		default:
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	return longest
+}
+
+// ToInfluxLineProtocol formats the draw of game g as a point of the InfluxDB
+// line protocol, for ingestion into a time-series database. The point has the
+// measurement opap_draw, the tag game, an integer field drawNo and one
+// integer field result_<i> for each result, and is timestamped in nanoseconds
+// with the draw time. It returns an error if DrawTime cannot be parsed.
+func (d *Draw) ToInfluxLineProtocol(g Game) (string, error) {
+	t, err := parseDrawTime(d.DrawTime)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "opap_draw,game=%s drawNo=%di", string(g), d.DrawNo)
+	for i, n := range d.Results {
+		fmt.Fprintf(&buf, ",result_%d=%di", i, n)
+	}
+	fmt.Fprintf(&buf, " %d", t.UnixNano())
+	return buf.String(), nil
+}
+
+// DrawTimeToUnix returns the draw time of d, which is in Greek local time, as
+// a Unix timestamp in seconds.
+func DrawTimeToUnix(d *Draw) (int64, error) {
+	t, err := parseDrawTime(d.DrawTime)
+	if err != nil {
+		return 0, err
+	}
+	return t.Unix(), nil
+}
+
+// DrawTimeToUnixMilli returns the draw time of d as a Unix timestamp in
+// milliseconds.
+func DrawTimeToUnixMilli(d *Draw) (int64, error) {
+	t, err := parseDrawTime(d.DrawTime)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixNano() / int64(time.Millisecond), nil
+}
+
+// Copy returns a deep copy of the draw. Assigning a Draw copies only the
+// header of its Results slice, so both values share the same results. Copy
+// allocates a new Results slice instead, which costs an allocation and a copy
+// per call; use it only when the results of either draw are going to be
+// modified.
+func (d *Draw) Copy() Draw {
+	c := *d
+	if d.Results != nil {
+		c.Results = make([]int, len(d.Results))
+		copy(c.Results, d.Results)
+	}
+	return c
+}
+
+// DrawFromJSON decodes a draw from data in the format that the Latest and
+// ByNumber endpoints of the OPAP REST service respond with, that is
+// {"draw":{...}}.
+func DrawFromJSON(data []byte) (*Draw, error) {
+	d := new(draws)
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, err
+	}
+	return &d.Draw, nil
+}
+
+// NewDrawFromMap builds a draw out of the "drawTime", "drawNo" and "results"
+// fields of m, as decoded into a generic map by data stores or by
+// encoding/json. The draw number and the results may be ints or whole
+// float64s. It returns an error if a field is missing or has the wrong type.
+func NewDrawFromMap(m map[string]interface{}) (*Draw, error) {
+	drawTime, drawNo, raw, err := drawMapFields(m)
+	if err != nil {
+		return nil, err
+	}
+	d := &Draw{DrawTime: drawTime, DrawNo: drawNo, Results: make([]int, len(raw))}
+	for i, v := range raw {
+		n, ok := mapInt(v)
+		if !ok {
+			return nil, fmt.Errorf("draw map result %d is %T, want a whole number", i, v)
+		}
+		d.Results[i] = n
+	}
+	return d, nil
+}
+
+// DrawForFuzz builds a draw of game g out of the bytes of data, one byte per
+// result, for fuzz tests of the functions and methods that analyse draws.
+// The results are always balls of the pools recorded in the GameInfo of g and
+// the main numbers are distinct, except for the digits of Proto and Super3,
+// which can repeat. Only the results of the draw are set. It returns
+// (nil, false) if g is not one of the SupportedGames, if g does not draw
+// balls or if data has fewer bytes than g has results.
+func DrawForFuzz(g Game, data []byte) (*Draw, bool) {
+	info, err := InfoFor(g)
+	if err != nil || info.PoolSize == 0 || len(data) < info.DrawCount+info.BonusCount {
+		return nil, false
+	}
+	results := make([]int, 0, info.DrawCount+info.BonusCount)
+	if info.FirstBall == 0 {
+		for _, b := range data[:info.DrawCount] {
+			results = append(results, int(b)%info.PoolSize)
+		}
+	} else {
+		// Each byte picks one of the balls left in the pool, so that the
+		// main numbers come out distinct.
+		pool := make([]int, info.PoolSize)
+		for i := range pool {
+			pool[i] = info.FirstBall + i
+		}
+		for _, b := range data[:info.DrawCount] {
+			i := int(b) % len(pool)
+			results = append(results, pool[i])
+			pool = append(pool[:i], pool[i+1:]...)
+		}
+	}
+	for _, b := range data[info.DrawCount : info.DrawCount+info.BonusCount] {
+		results = append(results, 1+int(b)%info.BonusPoolSize)
+	}
+	return &Draw{Results: results}, true
+}
+
+// drawMapFields returns the fields of a draw held in m, leaving the
+// conversion of the results to the caller.
+func drawMapFields(m map[string]interface{}) (drawTime string, drawNo int, results []interface{}, err error) {
+	v, ok := m["drawTime"]
+	if !ok {
+		return "", 0, nil, errors.New("draw map has no drawTime")
+	}
+	if drawTime, ok = v.(string); !ok {
+		return "", 0, nil, fmt.Errorf("draw map drawTime is %T, want string", v)
+	}
+	v, ok = m["drawNo"]
+	if !ok {
+		return "", 0, nil, errors.New("draw map has no drawNo")
+	}
+	if drawNo, ok = mapInt(v); !ok {
+		return "", 0, nil, fmt.Errorf("draw map drawNo is %T, want a whole number", v)
+	}
+	v, ok = m["results"]
+	if !ok {
+		return "", 0, nil, errors.New("draw map has no results")
+	}
+	if results, ok = v.([]interface{}); !ok {
+		return "", 0, nil, fmt.Errorf("draw map results is %T, want []interface{}", v)
+	}
+	return drawTime, drawNo, results, nil
+}
+
+// mapInt returns v as an int if it is an int or a float64 without a
+// fractional part.
+func mapInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		if n == float64(int(n)) {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// Pair holds the results of two draws at the same position.
+type Pair struct {
+	A, B int
+}
+
+// ZipDrawResults pairs the results of draws a and b by position. It returns
+// an error if the draws do not have the same number of results.
+func ZipDrawResults(a, b Draw) ([]Pair, error) {
+	if len(a.Results) != len(b.Results) {
+		return nil, fmt.Errorf("draws have %d and %d results", len(a.Results), len(b.Results))
+	}
+	pairs := make([]Pair, len(a.Results))
+	for i := range a.Results {
+		pairs[i] = Pair{A: a.Results[i], B: b.Results[i]}
+	}
+	return pairs, nil
+}
+
+// SortedSet returns the distinct results of the draw in ascending order,
+// leaving Results untouched. Draws of the OPAP REST service should not have
+// duplicate results; SortedSet is meant for draw data of other sources.
+func (d *Draw) SortedSet() []int {
+	seen := make(map[int]struct{}, len(d.Results))
+	set := make([]int, 0, len(d.Results))
+	for _, n := range d.Results {
+		if _, ok := seen[n]; !ok {
+			seen[n] = struct{}{}
+			set = append(set, n)
+		}
+	}
+	sort.Ints(set)
+	return set
+}
+
+// splitResults splits the results of the draw of game g into its main
+// results and the numbers that g draws out of a separate pool or as a bonus,
+// such as the joker number of Joker or the bonus number of Lotto, as recorded
+// in the GameInfo of g. All the results are main if g is unknown, has no
+// bonus numbers, or the draw does not have the full results of a draw of g.
+func (d *Draw) splitResults(g Game) (main, bonus []int) {
+	info, err := InfoFor(g)
+	if err != nil || info.BonusCount == 0 || len(d.Results) != info.DrawCount+info.BonusCount {
+		return d.Results, nil
+	}
+	return d.Results[:info.DrawCount], d.Results[info.DrawCount:]
+}
+
+// mainResults returns the main results of the draw of game g as split by
+// splitResults.
+func (d *Draw) mainResults(g Game) []int {
+	main, _ := d.splitResults(g)
+	return main
+}
+
+// mainSum returns the sum of the main results of the draw of game g.
+func (d *Draw) mainSum(g Game) int {
+	sum := 0
+	for _, n := range d.mainResults(g) {
+		sum += n
+	}
+	return sum
+}
+
+// InRange reports whether every result of the draw of game g is a ball of
+// the pool it is drawn out of: the main results out of the pool of the main
+// numbers and the joker or bonus numbers out of the bonus pool, as recorded
+// in the GameInfo of g. A draw with results out of range is reported as such
+// rather than as an error, as the draw data may be incomplete. The results of
+// Propogoal, Penalties and Bowling are not balls, so they are not checked. It
+// returns ErrUnknownGame if g is not one of the SupportedGames.
+func (d *Draw) InRange(g Game) (bool, error) {
+	info, err := InfoFor(g)
+	if err != nil {
+		return false, err
+	}
+	if info.PoolSize == 0 {
+		return true, nil
+	}
+	main, bonus := d.splitResults(g)
+	for _, n := range main {
+		if n < info.FirstBall || n > info.lastBall() {
+			return false, nil
+		}
+	}
+	for _, n := range bonus {
+		if n < 1 || n > info.BonusPoolSize {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// NumberQuartile returns which quarter, 1 to 4, of the pool of the main
+// numbers of game g the number n falls in, splitting the pool into four
+// buckets of equal width, so that draws can be studied by quarter. The result
+// does not depend on the draw. It returns ErrUnknownGame if g is not one of
+// the SupportedGames, ErrNoBallPool if g does not draw balls and an error if
+// n is not a ball of the pool.
+func (d *Draw) NumberQuartile(n int, g Game) (int, error) {
+	info, err := InfoFor(g)
+	if err != nil {
+		return 0, err
+	}
+	if info.PoolSize == 0 {
+		return 0, ErrNoBallPool
+	}
+	if n < info.FirstBall || n > info.lastBall() {
+		return 0, fmt.Errorf("number %d out of the %s pool [%d, %d]", n, g, info.FirstBall, info.lastBall())
+	}
+	return (n-info.FirstBall)*4/info.PoolSize + 1, nil
+}
+
+// SortedMainResults returns the main results of the draw of game g, that is
+// the results without a joker or bonus number, in ascending order. Results is
+// left untouched. It returns ErrUnknownGame if g is not one of the
+// SupportedGames.
+func (d *Draw) SortedMainResults(g Game) ([]int, error) {
+	if _, err := InfoFor(g); err != nil {
+		return nil, err
+	}
+	main, _ := d.splitResults(g)
+	return sortedCopy(main), nil
+}
+
+// SortedBonusResults is like SortedMainResults for the joker or bonus
+// numbers of the draw. The result is empty for games without bonus numbers.
+func (d *Draw) SortedBonusResults(g Game) ([]int, error) {
+	if _, err := InfoFor(g); err != nil {
+		return nil, err
+	}
+	_, bonus := d.splitResults(g)
+	return sortedCopy(bonus), nil
+}
+
+// MarkedDraw holds the results of a draw split into its main results and its
+// joker or bonus numbers, for display.
+type MarkedDraw struct {
+	Main     []int
+	Bonus    []int
+	DrawNo   int
+	DrawTime string
+}
+
+// Mark returns the draw of game g as a MarkedDraw, with the results split
+// like SortedMainResults and SortedBonusResults split them but kept in the
+// order they were drawn. It returns ErrUnknownGame if g is not one of the
+// SupportedGames.
+func (d *Draw) Mark(g Game) (MarkedDraw, error) {
+	if _, err := InfoFor(g); err != nil {
+		return MarkedDraw{}, err
+	}
+	main, bonus := d.splitResults(g)
+	return MarkedDraw{
+		Main:     append([]int(nil), main...),
+		Bonus:    append([]int(nil), bonus...),
+		DrawNo:   d.DrawNo,
+		DrawTime: d.DrawTime,
+	}, nil
+}
+
+// String formats the results of the draw separated by middle dots, with the
+// bonus numbers bracketed, for example "40 · 13 · 1 · 24 · 15 [8]".
+func (md MarkedDraw) String() string {
+	s := joinResults(md.Main)
+	if len(md.Bonus) > 0 {
+		s += " [" + joinResults(md.Bonus) + "]"
+	}
+	return s
+}
+
+// Summary describes the draw of game g in a single line, with its main
+// results in ascending order followed by its bonus numbers labelled with the
+// game, for example:
+//
+//	Draw #1873 on 24 Dec 2017 @ 22:00: 1 - 13 - 15 - 24 - 40 | JOKER: 8
+//
+// If g is not one of the SupportedGames, the results are listed in the order
+// they were drawn without a label, and if the DrawTime cannot be parsed, it is
+// shown as is.
+func (d *Draw) Summary(g Game) string {
+	when := d.DrawTime
+	if t, err := d.Time(); err == nil {
+		when = t.Format("02 Jan 2006 @ 15:04")
+	}
+	s := fmt.Sprintf("Draw #%d on %s: ", d.DrawNo, when)
+	if _, err := InfoFor(g); err != nil {
+		return s + joinSummary(d.Results)
+	}
+	main, bonus := d.splitResults(g)
+	s += joinSummary(sortedCopy(main))
+	if len(bonus) > 0 {
+		s += " | " + g.String() + ": " + joinSummary(bonus)
+	}
+	return s
+}
+
+// joinSummary formats results separated by dashes, as Summary shows them.
+func joinSummary(results []int) string {
+	parts := make([]string, len(results))
+	for i, n := range results {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, " - ")
+}
+
+// joinResults formats results separated by middle dots.
+func joinResults(results []int) string {
+	parts := make([]string, len(results))
+	for i, n := range results {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// sortedCopy returns a copy of results in ascending order.
+func sortedCopy(results []int) []int {
+	sorted := make([]int, len(results))
+	copy(sorted, results)
+	sort.Ints(sorted)
+	return sorted
+}
+
+// FuzzyMatch reports whether at least len(ticket)-maxMisses of the ticket
+// numbers appear in the main results of the draw of game g, that is the
+// results without a joker or bonus number. It is meant for near miss
+// analysis. It returns an error if maxMisses is negative or greater than the
+// number of ticket numbers.
+func (d *Draw) FuzzyMatch(ticket []int, maxMisses int, g Game) (bool, error) {
+	if maxMisses < 0 || maxMisses > len(ticket) {
+		return false, fmt.Errorf("invalid max misses %d for %d ticket numbers", maxMisses, len(ticket))
+	}
+	main := d.mainResults(g)
+	hits := 0
+	for _, n := range ticket {
+		for _, r := range main {
+			if n == r {
+				hits++
+				break
+			}
+		}
+	}
+	return hits >= len(ticket)-maxMisses, nil
+}
+
+// DrawSliceStats holds aggregate facts about a slice of draws, as computed by
+// NewDrawSliceStats.
+type DrawSliceStats struct {
+	Count            int
+	EarliestDrawNo   int
+	LatestDrawNo     int
+	EarliestDrawTime time.Time
+	LatestDrawTime   time.Time
+}
+
+// NewDrawSliceStats computes the DrawSliceStats of draws in a single pass.
+// The earliest and latest draw numbers and draw times are found independently
+// of each other, so they need not belong to the same draws. It returns
+// ErrEmptySlice if draws is empty, or an error if a DrawTime cannot be parsed.
+func NewDrawSliceStats(draws []Draw) (DrawSliceStats, error) {
+	var st DrawSliceStats
+	if len(draws) == 0 {
+		return st, ErrEmptySlice
+	}
+	for i := range draws {
+		t, err := draws[i].Time()
+		if err != nil {
+			return DrawSliceStats{}, err
+		}
+		st.add(draws[i].DrawNo, t)
+	}
+	return st, nil
+}
+
+// add records a draw numbered no that took place at t.
+func (st *DrawSliceStats) add(no int, t time.Time) {
+	if st.Count == 0 || no < st.EarliestDrawNo {
+		st.EarliestDrawNo = no
+	}
+	if st.Count == 0 || no > st.LatestDrawNo {
+		st.LatestDrawNo = no
+	}
+	if st.Count == 0 || t.Before(st.EarliestDrawTime) {
+		st.EarliestDrawTime = t
+	}
+	if st.Count == 0 || t.After(st.LatestDrawTime) {
+		st.LatestDrawTime = t
+	}
+	st.Count++
+}
+
+// AssertResultsLength returns an error if the draw does not have expected
+// results. It is meant for tests and runtime assertions of invariants after a
+// draw is fetched, not for the normal handling of draws.
+func (d *Draw) AssertResultsLength(expected int) error {
+	if len(d.Results) != expected {
+		return fmt.Errorf("draw %d has %d results, want %d", d.DrawNo, len(d.Results), expected)
+	}
+	return nil
+}
+
+// Before reports whether the draw took place before draw other. It returns an
+// error if the DrawTime of either draw cannot be parsed.
+func (d *Draw) Before(other Draw) (bool, error) {
+	t, u, err := parseDrawTimes(d.DrawTime, other.DrawTime)
+	if err != nil {
+		return false, err
+	}
+	return t.Before(u), nil
+}
+
+// After reports whether the draw took place after draw other. It returns an
+// error if the DrawTime of either draw cannot be parsed.
+func (d *Draw) After(other Draw) (bool, error) {
+	t, u, err := parseDrawTimes(d.DrawTime, other.DrawTime)
+	if err != nil {
+		return false, err
+	}
+	return t.After(u), nil
+}
+
+// parseDrawTimes parses the draw times a and b.
+func parseDrawTimes(a, b string) (time.Time, time.Time, error) {
+	t, err := parseDrawTime(a)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	u, err := parseDrawTime(b)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return t, u, nil
+}
+
+// SortDrawsByTime sorts draws in place from the earliest to the latest
+// DrawTime, keeping the order of draws that took place at the same time. The
+// draw times are parsed before sorting, so if one cannot be parsed the error
+// is returned and draws is left untouched.
+func SortDrawsByTime(draws []Draw) error {
+	return sortDrawsByTime(draws, parseDrawTime)
+}
+
+// sortDrawsByTime is SortDrawsByTime with draw times parsed by parse.
+func sortDrawsByTime(draws []Draw, parse func(string) (time.Time, error)) error {
+	times := make([]time.Time, len(draws))
+	for i := range draws {
+		t, err := parse(draws[i].DrawTime)
+		if err != nil {
+			return err
+		}
+		times[i] = t
+	}
+	sort.Stable(byTime{len(draws), times, func(i, j int) { draws[i], draws[j] = draws[j], draws[i] }})
+	return nil
+}
+
+// byTime sorts a slice of n draws by their parsed times, calling swap to swap
+// the draws along with their times.
+type byTime struct {
+	n     int
+	times []time.Time
+	swap  func(i, j int)
+}
+
+func (s byTime) Len() int           { return s.n }
+func (s byTime) Less(i, j int) bool { return s.times[i].Before(s.times[j]) }
+func (s byTime) Swap(i, j int) {
+	s.times[i], s.times[j] = s.times[j], s.times[i]
+	s.swap(i, j)
+}
+
+// DrawJSON is a Draw with its draw time parsed into a time.Time. It encodes to
+// and decodes from the same JSON as Draw, with the draw time in the layout of
+// the OPAP REST service.
+type DrawJSON struct {
+	DrawTime time.Time
+	DrawNo   int
+	Results  []int
+}
+
+// ToDrawJSON returns the draw as a DrawJSON. It returns an error if the
+// DrawTime of the draw cannot be parsed.
+func (d *Draw) ToDrawJSON() (DrawJSON, error) {
+	t, err := d.Time()
+	if err != nil {
+		return DrawJSON{}, err
+	}
+	return DrawJSON{DrawTime: t, DrawNo: d.DrawNo, Results: d.Results}, nil
+}
+
+// ToDraw returns the draw as a Draw, formatting its draw time in the layout
+// of the OPAP REST service. The time is converted to the location of
+// DefaultDrawTimeParser first, so that Draw.Time parses it back into the
+// same instant whatever the location of DrawTime.
+func (dj DrawJSON) ToDraw() Draw {
+	drawTime := dj.DrawTime.In(DefaultDrawTimeParser.location()).Format(drawTimeLayout)
+	return Draw{DrawTime: drawTime, DrawNo: dj.DrawNo, Results: dj.Results}
+}
+
+// MarshalJSON encodes the draw like Draw does.
+func (dj DrawJSON) MarshalJSON() ([]byte, error) {
+	d := dj.ToDraw()
+	return json.Marshal(&d)
+}
+
+// UnmarshalJSON decodes a draw encoded like Draw is. It returns an error if
+// the draw time cannot be parsed.
+func (dj *DrawJSON) UnmarshalJSON(data []byte) error {
+	var d Draw
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	v, err := d.ToDrawJSON()
+	if err != nil {
+		return err
+	}
+	*dj = v
+	return nil
+}
@@ -0,0 +1,1077 @@
+package opap
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var drawTimeTests = []struct {
+	in      string
+	want    time.Time
+	wantErr bool
+}{
+	{"24-12-2017T22:00:00", time.Date(2017, 12, 24, 22, 0, 0, 0, athens), false},
+	{"01-01-2018T00:00:05", time.Date(2018, 1, 1, 0, 0, 5, 0, athens), false},
+	{"", time.Time{}, true},
+	{"2017-12-24T22:00:00", time.Time{}, true},
+	{"24-12-2017 22:00:00", time.Time{}, true},
+	{"31-02-2017T22:00:00", time.Time{}, true},
+}
+
+func TestDraw_Time(t *testing.T) {
+	for _, tt := range drawTimeTests {
+		d := &Draw{DrawTime: tt.in}
+		got, err := d.Time()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Draw{DrawTime: %q}.Time() err = %v, want err %v", tt.in, err, tt.wantErr)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Draw{DrawTime: %q}.Time() = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDraw_resultHelpers(t *testing.T) {
+	tests := []struct {
+		results []int
+		sum     int
+		max     int
+		min     int
+		mean    float64
+	}{
+		{nil, 0, 0, 0, 0},
+		{[]int{}, 0, 0, 0, 0},
+		{[]int{7}, 7, 7, 7, 7},
+		{[]int{40, 13, 1, 24, 15, 8}, 101, 40, 1, 101.0 / 6},
+		{[]int{0, 9, 0}, 9, 9, 0, 3},
+	}
+	for _, tt := range tests {
+		d := &Draw{Results: tt.results}
+		if got := d.Sum(); got != tt.sum {
+			t.Errorf("Draw{Results: %v}.Sum() = %d, want %d", tt.results, got, tt.sum)
+		}
+		if got := d.Max(); got != tt.max {
+			t.Errorf("Draw{Results: %v}.Max() = %d, want %d", tt.results, got, tt.max)
+		}
+		if got := d.Min(); got != tt.min {
+			t.Errorf("Draw{Results: %v}.Min() = %d, want %d", tt.results, got, tt.min)
+		}
+		if got := d.Mean(); got != tt.mean {
+			t.Errorf("Draw{Results: %v}.Mean() = %v, want %v", tt.results, got, tt.mean)
+		}
+	}
+}
+
+func TestDraw_StandardDeviation(t *testing.T) {
+	tests := []struct {
+		game    Game
+		results []int
+		want    float64
+	}{
+		{Kino, []int{7}, 0},
+		{Kino, []int{2, 4, 4, 4, 5, 5, 7, 9}, 2},
+		// The joker number is left out, so it does not change the result.
+		{Joker, []int{2, 4, 6, 8, 10, 45}, math.Sqrt(8)},
+		{Joker, []int{2, 4, 6, 8, 10, 1}, math.Sqrt(8)},
+		// The bonus number of Lotto is left out too.
+		{Lotto, []int{1, 2, 3, 4, 5, 6, 49}, math.Sqrt(35.0 / 12)},
+	}
+	for _, tt := range tests {
+		d := &Draw{Results: tt.results}
+		got, err := d.StandardDeviation(tt.game)
+		if err != nil {
+			t.Errorf("Draw{Results: %v}.StandardDeviation(%q) returned err: %v", tt.results, tt.game, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Draw{Results: %v}.StandardDeviation(%q) = %v, want %v", tt.results, tt.game, got, tt.want)
+		}
+	}
+
+	if _, err := (&Draw{Results: []int{1, 2}}).StandardDeviation(Game("foo")); err != ErrUnknownGame {
+		t.Errorf("Draw.StandardDeviation(\"foo\") err = %v, want %v", err, ErrUnknownGame)
+	}
+	if _, err := (&Draw{}).StandardDeviation(Kino); err != ErrInsufficientResults {
+		t.Errorf("Draw{}.StandardDeviation(Kino) err = %v, want %v", err, ErrInsufficientResults)
+	}
+}
+
+func TestDraw_Contains(t *testing.T) {
+	d := &Draw{Results: []int{40, 13, 1, 24, 15, 8}}
+	for _, n := range d.Results {
+		if !d.Contains(n) {
+			t.Errorf("Draw{Results: %v}.Contains(%d) = false, want true", d.Results, n)
+		}
+	}
+	for _, n := range []int{0, 2, 45} {
+		if d.Contains(n) {
+			t.Errorf("Draw{Results: %v}.Contains(%d) = true, want false", d.Results, n)
+		}
+	}
+	if (&Draw{}).Contains(0) {
+		t.Error("Draw{}.Contains(0) = true, want false")
+	}
+}
+
+func TestDraw_JokerNumber(t *testing.T) {
+	tests := []struct {
+		results []int
+		want    int
+		wantOK  bool
+	}{
+		{[]int{40, 13, 1, 24, 15, 8}, 8, true},
+		{[]int{40, 13, 1, 24, 15}, 0, false},
+		{nil, 0, false},
+	}
+	for _, tt := range tests {
+		d := &Draw{Results: tt.results}
+		got, ok := d.JokerNumber()
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Draw{Results: %v}.JokerNumber() = %d, %v, want %d, %v", tt.results, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestDraw_FuzzyMatch(t *testing.T) {
+	tests := []struct {
+		game      Game
+		results   []int
+		ticket    []int
+		maxMisses int
+		want      bool
+	}{
+		{Joker, []int{40, 13, 1, 24, 15, 8}, []int{40, 13, 1, 24, 15}, 0, true},
+		{Joker, []int{40, 13, 1, 24, 15, 8}, []int{40, 13, 1, 24, 2}, 1, true},
+		{Joker, []int{40, 13, 1, 24, 15, 8}, []int{40, 13, 1, 3, 2}, 1, false},
+		// The joker number is not one of the main results.
+		{Joker, []int{40, 13, 1, 24, 15, 8}, []int{40, 13, 1, 24, 8}, 0, false},
+		// The bonus number of Lotto is not one of the main results.
+		{Lotto, []int{4, 9, 17, 23, 31, 44, 12}, []int{4, 9, 17, 23, 31, 12}, 0, false},
+		{Lotto, []int{4, 9, 17, 23, 31, 44, 12}, []int{4, 9, 17, 23, 31, 12}, 1, true},
+		{Kino, []int{1, 2, 3}, []int{7, 8}, 2, true},
+		{Kino, []int{1, 2, 3}, nil, 0, true},
+	}
+	for _, tt := range tests {
+		d := &Draw{Results: tt.results}
+		got, err := d.FuzzyMatch(tt.ticket, tt.maxMisses, tt.game)
+		if err != nil {
+			t.Errorf("Draw{Results: %v}.FuzzyMatch(%v, %d, %q) returned err: %v", tt.results, tt.ticket, tt.maxMisses, tt.game, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Draw{Results: %v}.FuzzyMatch(%v, %d, %q) = %v, want %v", tt.results, tt.ticket, tt.maxMisses, tt.game, got, tt.want)
+		}
+	}
+
+	d := &Draw{Results: []int{40, 13, 1, 24, 15, 8}}
+	for _, maxMisses := range []int{-1, 6} {
+		if _, err := d.FuzzyMatch([]int{1, 2, 3, 4, 5}, maxMisses, Joker); err == nil {
+			t.Errorf("Draw.FuzzyMatch with max misses %d expected error", maxMisses)
+		}
+	}
+}
+
+func TestDraw_WithGame(t *testing.T) {
+	d := &Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+
+	var game Game = Joker
+	want := GameDraw{Game: Joker, Draw: *d}
+	if got := d.WithGame(game); !reflect.DeepEqual(got, want) {
+		t.Errorf("Draw.WithGame(%q) \nhave: %#v\nwant: %#v", game, got, want)
+	}
+}
+
+func TestGameDraw_methods(t *testing.T) {
+	d := &Draw{DrawNo: 1873, DrawTime: "24-12-2017T22:00:00", Results: []int{40, 13, 1, 24, 15, 8}}
+	gd := d.WithGame(Joker)
+
+	sd, err := gd.StandardDeviation()
+	if want, _ := d.StandardDeviation(Joker); err != nil || sd != want {
+		t.Errorf("GameDraw.StandardDeviation() = %v, %v, want %v", sd, err, want)
+	}
+	sum, err := gd.SumNormalized()
+	if want, _ := d.SumNormalized(Joker); err != nil || sum != want {
+		t.Errorf("GameDraw.SumNormalized() = %v, %v, want %v", sum, err, want)
+	}
+	if ok, err := gd.InRange(); err != nil || !ok {
+		t.Errorf("GameDraw.InRange() = %v, %v, want true", ok, err)
+	}
+	main, err := gd.SortedMainResults()
+	if want := []int{1, 13, 15, 24, 40}; err != nil || !reflect.DeepEqual(main, want) {
+		t.Errorf("GameDraw.SortedMainResults() = %v, %v, want %v", main, err, want)
+	}
+	bonus, err := gd.SortedBonusResults()
+	if want := []int{8}; err != nil || !reflect.DeepEqual(bonus, want) {
+		t.Errorf("GameDraw.SortedBonusResults() = %v, %v, want %v", bonus, err, want)
+	}
+	md, err := gd.Mark()
+	if want, _ := d.Mark(Joker); err != nil || !reflect.DeepEqual(md, want) {
+		t.Errorf("GameDraw.Mark() \nhave: %#v\nwant: %#v", md, want)
+	}
+	// The joker number 8 does not count as a hit.
+	if ok, err := gd.FuzzyMatch([]int{40, 13, 1, 8}, 1); err != nil || !ok {
+		t.Errorf("GameDraw.FuzzyMatch() = %v, %v, want true", ok, err)
+	}
+	if !gd.HasJokerBall() {
+		t.Error("GameDraw.HasJokerBall() = false, want true")
+	}
+	line, err := gd.ToInfluxLineProtocol()
+	if want, _ := d.ToInfluxLineProtocol(Joker); err != nil || line != want {
+		t.Errorf("GameDraw.ToInfluxLineProtocol() = %q, %v, want %q", line, err, want)
+	}
+}
+
+func TestGameDraw_String(t *testing.T) {
+	d := &Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+	want := "JOKER draw 1873 [40 13 1 24 15 8]"
+	if got := fmt.Sprint(d.WithGame(Joker)); got != want {
+		t.Errorf("fmt.Sprint(GameDraw) = %q, want %q", got, want)
+	}
+}
+
+func TestGameDraw_JSON(t *testing.T) {
+	d := &Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+
+	b, err := json.Marshal(d.WithGame(Joker))
+	if err != nil {
+		t.Fatal("json.Marshal(GameDraw) returned err:", err)
+	}
+	want := `{"game":"joker","drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1,24,15,8]}`
+	if got := string(b); got != want {
+		t.Errorf("json.Marshal(GameDraw) \nhave: %s\nwant: %s", got, want)
+	}
+
+	for _, g := range AllGames() {
+		gd := d.WithGame(g)
+		b, err := json.Marshal(gd)
+		if err != nil {
+			t.Errorf("json.Marshal(GameDraw{%s}) returned err: %v", string(g), err)
+			continue
+		}
+		var got GameDraw
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Errorf("json.Unmarshal(%s) returned err: %v", b, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, gd) {
+			t.Errorf("GameDraw JSON round trip \nhave: %#v\nwant: %#v", got, gd)
+		}
+	}
+}
+
+func TestDraw_HasDuplicates(t *testing.T) {
+	tests := []struct {
+		results []int
+		want    bool
+	}{
+		{nil, false},
+		{[]int{40, 13, 1, 24, 15, 8}, false},
+		{[]int{40, 13, 1, 24, 40, 8}, true},
+		{[]int{7, 7}, true},
+	}
+	for _, tt := range tests {
+		d := &Draw{Results: tt.results}
+		if got := d.HasDuplicates(); got != tt.want {
+			t.Errorf("Draw{Results: %v}.HasDuplicates() = %v, want %v", tt.results, got, tt.want)
+		}
+	}
+}
+
+func TestDraw_ToJSON(t *testing.T) {
+	d := &Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+
+	b, err := d.ToJSON()
+	if err != nil {
+		t.Fatal("Draw.ToJSON returned err:", err)
+	}
+	s, err := d.ToJSONString()
+	if err != nil {
+		t.Fatal("Draw.ToJSONString returned err:", err)
+	}
+	want := `{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1,24,15,8]}`
+	if got := string(b); got != want {
+		t.Errorf("Draw.ToJSON() \nhave: %s\nwant: %s", got, want)
+	}
+	if got := s; got != want {
+		t.Errorf("Draw.ToJSONString() \nhave: %s\nwant: %s", got, want)
+	}
+
+	got := new(Draw)
+	if err := json.Unmarshal([]byte(s), got); err != nil {
+		t.Fatal("decoding Draw.ToJSONString output returned err:", err)
+	}
+	if !reflect.DeepEqual(got, d) {
+		t.Errorf("Draw JSON round trip \nhave: %#v\nwant: %#v", got, d)
+	}
+}
+
+func TestMostRecentOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		draws []Draw
+		want  *Draw
+	}{
+		{
+			name:  "one draw",
+			draws: []Draw{{DrawNo: 1873, DrawTime: "24-12-2017T22:00:00"}},
+			want:  &Draw{DrawNo: 1873, DrawTime: "24-12-2017T22:00:00"},
+		},
+		{
+			name: "highest draw number",
+			draws: []Draw{
+				{DrawNo: 1872, DrawTime: "21-12-2017T22:00:00"},
+				{DrawNo: 1873, DrawTime: "24-12-2017T22:00:00"},
+				{DrawNo: 1871, DrawTime: "17-12-2017T22:00:00"},
+			},
+			want: &Draw{DrawNo: 1873, DrawTime: "24-12-2017T22:00:00"},
+		},
+		{
+			name: "tie broken by draw time",
+			draws: []Draw{
+				{DrawNo: 1873, DrawTime: "24-12-2017T21:00:00"},
+				{DrawNo: 1873, DrawTime: "24-12-2017T22:00:00"},
+				{DrawNo: 1873, DrawTime: "24-12-2017T20:00:00"},
+			},
+			want: &Draw{DrawNo: 1873, DrawTime: "24-12-2017T22:00:00"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := MostRecentOf(tt.draws)
+		if err != nil {
+			t.Errorf("%s: MostRecentOf returned err: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: MostRecentOf \nhave: %#v\nwant: %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMostRecentOf_errors(t *testing.T) {
+	if _, err := MostRecentOf(nil); err != ErrEmptySlice {
+		t.Errorf("MostRecentOf(nil) err = %v, want %v", err, ErrEmptySlice)
+	}
+
+	draws := []Draw{{DrawNo: 1873, DrawTime: "24-12-2017T22:00:00"}, {DrawNo: 1873, DrawTime: "foo"}}
+	if _, err := MostRecentOf(draws); err == nil {
+		t.Error("MostRecentOf with malformed draw time expected to return err.")
+	}
+}
+
+func TestDraw_HasJokerBall(t *testing.T) {
+	tests := []struct {
+		game    Game
+		results []int
+		want    bool
+	}{
+		{Joker, []int{40, 13, 1, 24, 15, 8}, true},
+		{Joker, []int{40, 13, 1, 24, 15}, false},
+		{Joker, nil, false},
+		{Lotto, []int{40, 13, 1, 24, 15, 8}, false},
+	}
+	for _, tt := range tests {
+		d := &Draw{Results: tt.results}
+		if got := d.HasJokerBall(tt.game); got != tt.want {
+			t.Errorf("Draw{Results: %v}.HasJokerBall(%q) = %v, want %v", tt.results, tt.game, got, tt.want)
+		}
+	}
+}
+
+func TestGroupDrawsByPattern(t *testing.T) {
+	draws := []Draw{
+		{DrawNo: 1, Results: []int{1, 2, 3, 10}},
+		{DrawNo: 2, Results: []int{5, 7, 9}},
+		{DrawNo: 3, Results: []int{20, 21, 22, 40}},
+		{DrawNo: 4, Results: []int{30, 8, 31, 8}},
+		{DrawNo: 5},
+	}
+
+	want := map[string][]Draw{
+		"consecutive_run_3": {draws[0], draws[2]},
+		"consecutive_run_1": {draws[1]},
+		"consecutive_run_2": {draws[3]},
+		"consecutive_run_0": {draws[4]},
+	}
+	if got := GroupDrawsByPattern(draws, BucketByConsecutiveRunLength); !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupDrawsByPattern(draws, BucketByConsecutiveRunLength) \nhave: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestBucketBySumQuintile(t *testing.T) {
+	// The sums of the main numbers of Joker range over [15, 215].
+	draws := []Draw{
+		{DrawNo: 1, Results: []int{1, 2, 3, 4, 5, 20}},
+		{DrawNo: 2, Results: []int{40, 13, 1, 24, 15, 8}},
+		{DrawNo: 3, Results: []int{41, 42, 43, 44, 45, 1}},
+		{DrawNo: 4, Results: []int{30, 31, 32, 33, 34, 1}},
+		{DrawNo: 5},
+	}
+
+	want := map[string][]Draw{
+		"sum_quintile_1": {draws[0]},
+		"sum_quintile_2": {draws[1]},
+		"sum_quintile_4": {draws[3]},
+		"sum_quintile_5": {draws[2]},
+		"sum_quintile_0": {draws[4]},
+	}
+	if got := GroupDrawsByPattern(draws, BucketBySumQuintile(Joker)); !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupDrawsByPattern(draws, BucketBySumQuintile(%q)) \nhave: %#v\nwant: %#v", Joker, got, want)
+	}
+}
+
+func TestBucketByLowHighBalance(t *testing.T) {
+	draws := []Draw{
+		{DrawNo: 1, Results: []int{1, 22, 23, 45, 2, 20}},
+		{DrawNo: 2, Results: []int{40, 41, 42, 43, 44, 1}},
+		{DrawNo: 3, Results: []int{5, 6, 7, 30, 31, 19}},
+	}
+
+	want := map[string][]Draw{
+		"low_3_high_2": {draws[0], draws[2]},
+		"low_0_high_5": {draws[1]},
+	}
+	if got := GroupDrawsByPattern(draws, BucketByLowHighBalance(Joker)); !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupDrawsByPattern(draws, BucketByLowHighBalance(%q)) \nhave: %#v\nwant: %#v", Joker, got, want)
+	}
+
+	if got, want := BucketByLowHighBalance(Propogoal)(Draw{Results: []int{1, 0}}), "low_0_high_0"; got != want {
+		t.Errorf("BucketByLowHighBalance(%q) = %q, want %q", Propogoal, got, want)
+	}
+}
+
+func TestDraw_ToInfluxLineProtocol(t *testing.T) {
+	d := &Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+
+	var game Game = Joker
+	got, err := d.ToInfluxLineProtocol(game)
+	if err != nil {
+		t.Fatal("Draw.ToInfluxLineProtocol returned err:", err)
+	}
+	want := "opap_draw,game=joker drawNo=1873i,result_0=40i,result_1=13i,result_2=1i,result_3=24i,result_4=15i,result_5=8i 1514145600000000000"
+	if got != want {
+		t.Errorf("Draw.ToInfluxLineProtocol(%q) \nhave: %s\nwant: %s", game, got, want)
+	}
+}
+
+func TestDraw_ToInfluxLineProtocol_badDrawTime(t *testing.T) {
+	d := &Draw{DrawTime: "24/12/2017 22:00", DrawNo: 1873}
+	if _, err := d.ToInfluxLineProtocol(Joker); err == nil {
+		t.Error("Draw.ToInfluxLineProtocol with malformed draw time expected to return err.")
+	}
+}
+
+func TestDrawTimeToUnix(t *testing.T) {
+	// 22:00 in Athens is 20:00 UTC in the winter.
+	d := &Draw{DrawTime: "24-12-2017T22:00:00"}
+
+	sec, err := DrawTimeToUnix(d)
+	if err != nil {
+		t.Fatal("DrawTimeToUnix returned err:", err)
+	}
+	if got, want := sec, int64(1514145600); got != want {
+		t.Errorf("DrawTimeToUnix(%q) = %d, want %d", d.DrawTime, got, want)
+	}
+
+	ms, err := DrawTimeToUnixMilli(d)
+	if err != nil {
+		t.Fatal("DrawTimeToUnixMilli returned err:", err)
+	}
+	if got, want := ms, int64(1514145600000); got != want {
+		t.Errorf("DrawTimeToUnixMilli(%q) = %d, want %d", d.DrawTime, got, want)
+	}
+}
+
+func TestDrawTimeToUnix_badDrawTime(t *testing.T) {
+	d := &Draw{DrawTime: ""}
+	if _, err := DrawTimeToUnix(d); err == nil {
+		t.Error("DrawTimeToUnix with empty draw time expected to return err.")
+	}
+	if _, err := DrawTimeToUnixMilli(d); err == nil {
+		t.Error("DrawTimeToUnixMilli with empty draw time expected to return err.")
+	}
+}
+
+func TestDraw_Copy(t *testing.T) {
+	d := &Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+
+	c := d.Copy()
+	if !reflect.DeepEqual(&c, d) {
+		t.Fatalf("Draw.Copy() \nhave: %#v\nwant: %#v", c, d)
+	}
+	c.Results[0] = 45
+	if got, want := d.Results[0], 40; got != want {
+		t.Errorf("modifying copy changed original Results[0] to %d, want %d", got, want)
+	}
+
+	empty := &Draw{}
+	if c := empty.Copy(); c.Results != nil {
+		t.Errorf("Draw{}.Copy().Results = %#v, want nil", c.Results)
+	}
+}
+
+func TestDrawFromJSON(t *testing.T) {
+	data := []byte(`{"draw":{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1,24,15,8]}}`)
+
+	got, err := DrawFromJSON(data)
+	if err != nil {
+		t.Fatal("DrawFromJSON returned err:", err)
+	}
+	want := &Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DrawFromJSON(%s) \nhave: %#v\nwant: %#v", data, got, want)
+	}
+
+	if _, err := DrawFromJSON([]byte(`<html>something broke</html>`)); err == nil {
+		t.Error("DrawFromJSON with HTML expected to return err.")
+	}
+}
+
+func TestParseDrawTime(t *testing.T) {
+	want := time.Date(2017, 12, 24, 22, 0, 0, 0, athens)
+	for _, s := range []string{
+		"24-12-2017T22:00:00",
+		"24-12-2017T22:00:00 ",
+		"24-12-2017T22:00:00\n",
+		" 24-12-2017T22:00:00",
+	} {
+		got, err := parseDrawTime(s)
+		if err != nil {
+			t.Errorf("parseDrawTime(%q) returned err: %v", s, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("parseDrawTime(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestZipDrawResults(t *testing.T) {
+	a := Draw{Results: []int{40, 13, 1}}
+	b := Draw{Results: []int{2, 13, 45}}
+
+	got, err := ZipDrawResults(a, b)
+	if err != nil {
+		t.Fatal("ZipDrawResults returned err:", err)
+	}
+	want := []Pair{{40, 2}, {13, 13}, {1, 45}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ZipDrawResults(%v, %v) = %v, want %v", a, b, got, want)
+	}
+
+	if _, err := ZipDrawResults(a, Draw{Results: []int{1}}); err == nil {
+		t.Error("ZipDrawResults with different lengths expected to return err.")
+	}
+}
+
+func TestDraw_SumNormalized(t *testing.T) {
+	tests := []struct {
+		game    Game
+		results []int
+		want    float64
+	}{
+		// The joker number is left out: the sums range over [15, 215].
+		{Joker, []int{1, 2, 3, 4, 5, 20}, 0},
+		{Joker, []int{41, 42, 43, 44, 45, 1}, 1},
+		{Joker, []int{40, 13, 1, 24, 15, 8}, 0.39},
+		{Powerspin, []int{24}, 1},
+		// Digits can repeat: the sums range over [0, 27].
+		{Super3, []int{0, 0, 0}, 0},
+		{Super3, []int{9, 9, 9}, 1},
+	}
+	for _, tt := range tests {
+		d := &Draw{Results: tt.results}
+		got, err := d.SumNormalized(tt.game)
+		if err != nil {
+			t.Errorf("Draw{Results: %v}.SumNormalized(%q) returned err: %v", tt.results, tt.game, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Draw{Results: %v}.SumNormalized(%q) = %v, want %v", tt.results, tt.game, got, tt.want)
+		}
+	}
+}
+
+func TestDraw_SumNormalized_error(t *testing.T) {
+	tests := []struct {
+		game    Game
+		results []int
+		err     error
+	}{
+		{Game("foo"), []int{1}, ErrUnknownGame},
+		{Propogoal, []int{1, 0}, ErrNoBallPool},
+		{Joker, nil, ErrInsufficientResults},
+	}
+	for _, tt := range tests {
+		d := &Draw{Results: tt.results}
+		if _, err := d.SumNormalized(tt.game); err != tt.err {
+			t.Errorf("Draw{Results: %v}.SumNormalized(%q) err = %v, want %v", tt.results, tt.game, err, tt.err)
+		}
+	}
+}
+
+func TestDraw_InRange(t *testing.T) {
+	tests := []struct {
+		game    Game
+		results []int
+		want    bool
+	}{
+		{Joker, []int{40, 13, 1, 24, 15, 8}, true},
+		{Joker, []int{45, 13, 1, 24, 15, 20}, true},
+		{Joker, []int{46, 13, 1, 24, 15, 8}, false},
+		{Joker, []int{0, 13, 1, 24, 15, 8}, false},
+		// The joker number is drawn out of a pool of 20.
+		{Joker, []int{40, 13, 1, 24, 15, 21}, false},
+		{Lotto, []int{4, 9, 17, 23, 31, 44, 49}, true},
+		{Proto, []int{0, 9, 5, 3, 0, 1, 2}, true},
+		{Proto, []int{10, 9, 5, 3, 0, 1, 2}, false},
+		{Kino, nil, true},
+		{Propogoal, []int{3, 1}, true},
+	}
+	for _, tt := range tests {
+		d := &Draw{Results: tt.results}
+		got, err := d.InRange(tt.game)
+		if err != nil {
+			t.Errorf("Draw{Results: %v}.InRange(%q) returned err: %v", tt.results, tt.game, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Draw{Results: %v}.InRange(%q) = %v, want %v", tt.results, tt.game, got, tt.want)
+		}
+	}
+
+	if _, err := (&Draw{}).InRange(Game("foo")); err != ErrUnknownGame {
+		t.Errorf("Draw.InRange(\"foo\") err = %v, want %v", err, ErrUnknownGame)
+	}
+}
+
+func TestDraw_NumberQuartile(t *testing.T) {
+	tests := []struct {
+		game Game
+		n    int
+		want int
+	}{
+		{Kino, 1, 1},
+		{Kino, 20, 1},
+		{Kino, 21, 2},
+		{Kino, 60, 3},
+		{Kino, 61, 4},
+		{Kino, 80, 4},
+		{Joker, 12, 1},
+		{Joker, 13, 2},
+		{Joker, 45, 4},
+		{Super3, 0, 1},
+		{Super3, 9, 4},
+	}
+	d := &Draw{}
+	for _, tt := range tests {
+		got, err := d.NumberQuartile(tt.n, tt.game)
+		if err != nil {
+			t.Errorf("Draw.NumberQuartile(%d, %q) returned err: %v", tt.n, tt.game, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Draw.NumberQuartile(%d, %q) = %d, want %d", tt.n, tt.game, got, tt.want)
+		}
+	}
+}
+
+func TestDraw_NumberQuartile_error(t *testing.T) {
+	d := &Draw{}
+	if _, err := d.NumberQuartile(1, Game("foo")); err != ErrUnknownGame {
+		t.Errorf("Draw.NumberQuartile(1, \"foo\") err = %v, want %v", err, ErrUnknownGame)
+	}
+	if _, err := d.NumberQuartile(1, Bowling); err != ErrNoBallPool {
+		t.Errorf("Draw.NumberQuartile(1, %q) err = %v, want %v", Bowling, err, ErrNoBallPool)
+	}
+	for _, n := range []int{0, 81} {
+		if _, err := d.NumberQuartile(n, Kino); err == nil {
+			t.Errorf("Draw.NumberQuartile(%d, %q) expected err", n, Kino)
+		}
+	}
+}
+
+func TestDraw_Summary(t *testing.T) {
+	tests := []struct {
+		game Game
+		d    Draw
+		want string
+	}{
+		{
+			Joker,
+			Draw{DrawNo: 1873, DrawTime: "24-12-2017T22:00:00", Results: []int{40, 13, 1, 24, 15, 8}},
+			"Draw #1873 on 24 Dec 2017 @ 22:00: 1 - 13 - 15 - 24 - 40 | JOKER: 8",
+		},
+		{
+			Extra5,
+			Draw{DrawNo: 512, DrawTime: "05-01-2018T21:00:00", Results: []int{30, 2, 17, 9, 24}},
+			"Draw #512 on 05 Jan 2018 @ 21:00: 2 - 9 - 17 - 24 - 30",
+		},
+		{
+			Game("foo"),
+			Draw{DrawNo: 7, DrawTime: "24-12-2017T22:00:00", Results: []int{40, 13, 8}},
+			"Draw #7 on 24 Dec 2017 @ 22:00: 40 - 13 - 8",
+		},
+		{
+			Joker,
+			Draw{DrawNo: 1873, DrawTime: "foo", Results: []int{40, 13, 1, 24, 15, 8}},
+			"Draw #1873 on foo: 1 - 13 - 15 - 24 - 40 | JOKER: 8",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.d.Summary(tt.game); got != tt.want {
+			t.Errorf("Draw.Summary(%q) \nhave: %s\nwant: %s", tt.game, got, tt.want)
+		}
+	}
+}
+
+func TestDraw_SortedMainResults(t *testing.T) {
+	tests := []struct {
+		game      Game
+		results   []int
+		wantMain  []int
+		wantBonus []int
+	}{
+		{Joker, []int{40, 13, 1, 24, 15, 8}, []int{1, 13, 15, 24, 40}, []int{8}},
+		{Lotto, []int{44, 9, 17, 4, 31, 23, 12}, []int{4, 9, 17, 23, 31, 44}, []int{12}},
+		{Kino, []int{3, 1, 2}, []int{1, 2, 3}, []int{}},
+		// A Joker draw without the joker number has only main results.
+		{Joker, []int{40, 13, 1, 24, 15}, []int{1, 13, 15, 24, 40}, []int{}},
+	}
+	for _, tt := range tests {
+		results := append([]int(nil), tt.results...)
+		d := &Draw{Results: results}
+		main, err := d.SortedMainResults(tt.game)
+		if err != nil {
+			t.Fatalf("Draw{Results: %v}.SortedMainResults(%q) returned err: %v", tt.results, tt.game, err)
+		}
+		if !reflect.DeepEqual(main, tt.wantMain) {
+			t.Errorf("Draw{Results: %v}.SortedMainResults(%q) = %v, want %v", tt.results, tt.game, main, tt.wantMain)
+		}
+		bonus, err := d.SortedBonusResults(tt.game)
+		if err != nil {
+			t.Fatalf("Draw{Results: %v}.SortedBonusResults(%q) returned err: %v", tt.results, tt.game, err)
+		}
+		if !reflect.DeepEqual(bonus, tt.wantBonus) {
+			t.Errorf("Draw{Results: %v}.SortedBonusResults(%q) = %v, want %v", tt.results, tt.game, bonus, tt.wantBonus)
+		}
+		if !reflect.DeepEqual(d.Results, tt.results) {
+			t.Errorf("Draw.SortedMainResults modified Results to %v, want %v", d.Results, tt.results)
+		}
+	}
+
+	d := &Draw{Results: []int{1, 2}}
+	if _, err := d.SortedMainResults(Game("foo")); err != ErrUnknownGame {
+		t.Errorf("Draw.SortedMainResults(\"foo\") err = %v, want %v", err, ErrUnknownGame)
+	}
+	if _, err := d.SortedBonusResults(Game("foo")); err != ErrUnknownGame {
+		t.Errorf("Draw.SortedBonusResults(\"foo\") err = %v, want %v", err, ErrUnknownGame)
+	}
+}
+
+func TestDraw_Mark(t *testing.T) {
+	d := &Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+	got, err := d.Mark(Joker)
+	if err != nil {
+		t.Fatal("Draw.Mark returned err:", err)
+	}
+	want := MarkedDraw{Main: []int{40, 13, 1, 24, 15}, Bonus: []int{8}, DrawNo: 1873, DrawTime: "24-12-2017T22:00:00"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Draw.Mark(%q) \nhave: %#v\nwant: %#v", Joker, got, want)
+	}
+	if got, want := got.String(), "40 · 13 · 1 · 24 · 15 [8]"; got != want {
+		t.Errorf("MarkedDraw.String() = %q, want %q", got, want)
+	}
+
+	kino, err := (&Draw{Results: []int{3, 1, 2}}).Mark(Kino)
+	if err != nil {
+		t.Fatal("Draw.Mark returned err:", err)
+	}
+	if got, want := kino.String(), "3 · 1 · 2"; got != want {
+		t.Errorf("MarkedDraw.String() = %q, want %q", got, want)
+	}
+
+	if _, err := d.Mark(Game("foo")); err != ErrUnknownGame {
+		t.Errorf("Draw.Mark(\"foo\") err = %v, want %v", err, ErrUnknownGame)
+	}
+}
+
+func TestDraw_SortedSet(t *testing.T) {
+	d := &Draw{Results: []int{40, 13, 1, 40, 15, 1}}
+
+	want := []int{1, 13, 15, 40}
+	if got := d.SortedSet(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Draw.SortedSet() = %v, want %v", got, want)
+	}
+	if want := []int{40, 13, 1, 40, 15, 1}; !reflect.DeepEqual(d.Results, want) {
+		t.Errorf("Draw.SortedSet() modified Results to %v, want %v", d.Results, want)
+	}
+	if got := (&Draw{}).SortedSet(); len(got) != 0 {
+		t.Errorf("Draw{}.SortedSet() = %v, want empty", got)
+	}
+}
+
+func TestNewDrawSliceStats(t *testing.T) {
+	draws := []Draw{
+		{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873},
+		{DrawTime: "21-12-2017T22:00:00", DrawNo: 1872},
+		{DrawTime: "28-12-2017T22:00:00", DrawNo: 1874},
+	}
+	got, err := NewDrawSliceStats(draws)
+	if err != nil {
+		t.Fatal("NewDrawSliceStats returned err:", err)
+	}
+	want := DrawSliceStats{
+		Count:            3,
+		EarliestDrawNo:   1872,
+		LatestDrawNo:     1874,
+		EarliestDrawTime: time.Date(2017, 12, 21, 22, 0, 0, 0, athens),
+		LatestDrawTime:   time.Date(2017, 12, 28, 22, 0, 0, 0, athens),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewDrawSliceStats \nhave: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestNewDrawSliceStats_errors(t *testing.T) {
+	if _, err := NewDrawSliceStats(nil); err != ErrEmptySlice {
+		t.Errorf("NewDrawSliceStats(nil) err = %v, want %v", err, ErrEmptySlice)
+	}
+	if _, err := NewDrawSliceStats([]Draw{{DrawTime: "foo"}}); err == nil {
+		t.Error("NewDrawSliceStats with invalid DrawTime expected error")
+	}
+}
+
+func TestDraw_AssertResultsLength(t *testing.T) {
+	d := &Draw{DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+	if err := d.AssertResultsLength(6); err != nil {
+		t.Errorf("Draw.AssertResultsLength(6) returned err: %v", err)
+	}
+	if err := d.AssertResultsLength(5); err == nil {
+		t.Error("Draw.AssertResultsLength(5) expected error")
+	}
+	if err := (&Draw{}).AssertResultsLength(0); err != nil {
+		t.Errorf("Draw{}.AssertResultsLength(0) returned err: %v", err)
+	}
+}
+
+func TestDrawForFuzz(t *testing.T) {
+	data := make([]byte, 32)
+	for i := range data {
+		data[i] = byte(i * 37)
+	}
+	for _, g := range []Game{Kino, Lotto, Joker, Proto, Super3, Extra5, Powerspin} {
+		d, ok := DrawForFuzz(g, data)
+		if !ok {
+			t.Errorf("DrawForFuzz(%q) returned false", g)
+			continue
+		}
+		if got, want := len(d.Results), g.ResultCount(); got != want {
+			t.Errorf("DrawForFuzz(%q) has %d results, want %d", g, got, want)
+		}
+		if inRange, _ := d.InRange(g); !inRange {
+			t.Errorf("DrawForFuzz(%q) results %v out of range", g, d.Results)
+		}
+	}
+
+	d, _ := DrawForFuzz(Kino, make([]byte, 20))
+	seen := make(map[int]bool)
+	for _, n := range d.Results {
+		seen[n] = true
+	}
+	if len(seen) != 20 {
+		t.Errorf("DrawForFuzz(%q) results %v are not distinct", Kino, d.Results)
+	}
+}
+
+func TestDrawForFuzz_insufficient(t *testing.T) {
+	tests := []struct {
+		game Game
+		data []byte
+	}{
+		{Joker, make([]byte, 5)},
+		{Kino, nil},
+		{Propogoal, make([]byte, 32)},
+		{Game("foo"), make([]byte, 32)},
+	}
+	for _, tt := range tests {
+		if d, ok := DrawForFuzz(tt.game, tt.data); d != nil || ok {
+			t.Errorf("DrawForFuzz(%q, %d bytes) = %v, %v, want nil, false", tt.game, len(tt.data), d, ok)
+		}
+	}
+}
+
+func TestNewDrawFromMap(t *testing.T) {
+	want := &Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+
+	var fromJSON map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1,24,15,8]}`), &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	withInts := map[string]interface{}{
+		"drawTime": "24-12-2017T22:00:00",
+		"drawNo":   1873,
+		"results":  []interface{}{40, 13, 1, 24, 15, 8},
+	}
+	for _, m := range []map[string]interface{}{fromJSON, withInts} {
+		got, err := NewDrawFromMap(m)
+		if err != nil {
+			t.Errorf("NewDrawFromMap(%v) returned err: %v", m, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("NewDrawFromMap(%v) \nhave: %#v\nwant: %#v", m, got, want)
+		}
+	}
+}
+
+func TestNewDrawFromMap_errors(t *testing.T) {
+	tests := []map[string]interface{}{
+		{"drawNo": 1873, "results": []interface{}{}},
+		{"drawTime": 1, "drawNo": 1873, "results": []interface{}{}},
+		{"drawTime": "24-12-2017T22:00:00", "results": []interface{}{}},
+		{"drawTime": "24-12-2017T22:00:00", "drawNo": "1873", "results": []interface{}{}},
+		{"drawTime": "24-12-2017T22:00:00", "drawNo": 1873.5, "results": []interface{}{}},
+		{"drawTime": "24-12-2017T22:00:00", "drawNo": 1873},
+		{"drawTime": "24-12-2017T22:00:00", "drawNo": 1873, "results": []int{40}},
+		{"drawTime": "24-12-2017T22:00:00", "drawNo": 1873, "results": []interface{}{"40"}},
+	}
+	for _, m := range tests {
+		if _, err := NewDrawFromMap(m); err == nil {
+			t.Errorf("NewDrawFromMap(%v) expected error", m)
+		}
+	}
+}
+
+func TestDraw_BeforeAfter(t *testing.T) {
+	earlier := &Draw{DrawTime: "21-12-2017T22:00:00"}
+	later := Draw{DrawTime: "24-12-2017T22:00:00"}
+
+	if before, err := earlier.Before(later); err != nil || !before {
+		t.Errorf("Draw.Before(later) = %v, %v, want true, nil", before, err)
+	}
+	if after, err := earlier.After(later); err != nil || after {
+		t.Errorf("Draw.After(later) = %v, %v, want false, nil", after, err)
+	}
+	if before, err := earlier.Before(*earlier); err != nil || before {
+		t.Errorf("Draw.Before(itself) = %v, %v, want false, nil", before, err)
+	}
+
+	if _, err := earlier.Before(Draw{DrawTime: "foo"}); err == nil {
+		t.Error("Draw.Before with invalid DrawTime expected error")
+	}
+	if _, err := (&Draw{DrawTime: "foo"}).After(later); err == nil {
+		t.Error("Draw.After with invalid DrawTime expected error")
+	}
+}
+
+func TestSortDrawsByTime(t *testing.T) {
+	draws := []Draw{
+		{DrawTime: "24-12-2017T22:00:00", DrawNo: 3},
+		{DrawTime: "21-12-2017T22:00:00", DrawNo: 1},
+		{DrawTime: "24-12-2017T22:00:00", DrawNo: 4},
+		{DrawTime: "22-12-2017T22:00:00", DrawNo: 2},
+	}
+	if err := SortDrawsByTime(draws); err != nil {
+		t.Fatal("SortDrawsByTime returned err:", err)
+	}
+	for i, d := range draws {
+		if d.DrawNo != i+1 {
+			t.Errorf("SortDrawsByTime put draw %d at index %d", d.DrawNo, i)
+		}
+	}
+
+	invalid := []Draw{{DrawTime: "24-12-2017T22:00:00", DrawNo: 2}, {DrawTime: "foo", DrawNo: 1}}
+	if err := SortDrawsByTime(invalid); err == nil {
+		t.Error("SortDrawsByTime with invalid DrawTime expected error")
+	}
+	if invalid[0].DrawNo != 2 {
+		t.Error("SortDrawsByTime modified draws despite returning an error")
+	}
+}
+
+func TestDrawTimeParser_Parse(t *testing.T) {
+	athens, err := time.LoadLocation("Europe/Athens")
+	if err != nil {
+		t.Skip("time zone data unavailable:", err)
+	}
+	tests := []struct {
+		p    DrawTimeParser
+		in   string
+		want time.Time
+	}{
+		{DefaultDrawTimeParser, " 24-12-2017T22:00:00\n", time.Date(2017, 12, 24, 22, 0, 0, 0, athens)},
+		{DrawTimeParser{Layout: "2006-01-02 15:04"}, "2017-12-24 22:00", time.Date(2017, 12, 24, 22, 0, 0, 0, time.UTC)},
+		{DrawTimeParser{Layout: "2006-01-02 15:04", Location: athens}, "2017-12-24 22:00", time.Date(2017, 12, 24, 22, 0, 0, 0, athens)},
+	}
+	for _, tt := range tests {
+		got, err := tt.p.Parse(tt.in)
+		if err != nil {
+			t.Errorf("%v.Parse(%q) returned err: %v", tt.p, tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) || got.Location().String() != tt.want.Location().String() {
+			t.Errorf("%v.Parse(%q) = %v, want %v", tt.p, tt.in, got, tt.want)
+		}
+	}
+	if _, err := DefaultDrawTimeParser.Parse("2017-12-24 22:00"); err == nil {
+		t.Error("DefaultDrawTimeParser.Parse with another layout expected error")
+	}
+}
+
+func TestDrawJSON(t *testing.T) {
+	data := `{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1,24,15,8]}`
+
+	var dj DrawJSON
+	if err := json.Unmarshal([]byte(data), &dj); err != nil {
+		t.Fatal("json.Unmarshal(DrawJSON) returned err:", err)
+	}
+	want := DrawJSON{
+		DrawTime: time.Date(2017, 12, 24, 22, 0, 0, 0, athens),
+		DrawNo:   1873,
+		Results:  []int{40, 13, 1, 24, 15, 8},
+	}
+	if !reflect.DeepEqual(dj, want) {
+		t.Errorf("json.Unmarshal(DrawJSON) \nhave: %#v\nwant: %#v", dj, want)
+	}
+
+	b, err := json.Marshal(dj)
+	if err != nil {
+		t.Fatal("json.Marshal(DrawJSON) returned err:", err)
+	}
+	if got := string(b); got != data {
+		t.Errorf("json.Marshal(DrawJSON) \nhave: %s\nwant: %s", got, data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"drawTime":"foo"}`), &dj); err == nil {
+		t.Error("json.Unmarshal(DrawJSON) with invalid drawTime expected error")
+	}
+}
+
+func TestDraw_ToDrawJSON(t *testing.T) {
+	d := &Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+	dj, err := d.ToDrawJSON()
+	if err != nil {
+		t.Fatal("Draw.ToDrawJSON returned err:", err)
+	}
+	if got := dj.ToDraw(); !reflect.DeepEqual(got, *d) {
+		t.Errorf("Draw.ToDrawJSON().ToDraw() \nhave: %#v\nwant: %#v", got, *d)
+	}
+
+	// A draw time in another location is formatted in Greek local time.
+	utc := DrawJSON{DrawTime: time.Date(2017, 12, 24, 20, 0, 0, 0, time.UTC), DrawNo: 1873}
+	got := utc.ToDraw()
+	if want := "24-12-2017T22:00:00"; got.DrawTime != want {
+		t.Errorf("DrawJSON{DrawTime: %v}.ToDraw().DrawTime = %q, want %q", utc.DrawTime, got.DrawTime, want)
+	}
+	if tm, err := got.Time(); err != nil || !tm.Equal(utc.DrawTime) {
+		t.Errorf("DrawJSON{DrawTime: %v}.ToDraw().Time() = %v, %v, want %v", utc.DrawTime, tm, err, utc.DrawTime)
+	}
+
+	if _, err := (&Draw{DrawTime: "foo"}).ToDrawJSON(); err == nil {
+		t.Error("Draw.ToDrawJSON with invalid DrawTime expected error")
+	}
+}
@@ -0,0 +1,62 @@
+package opap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// DrawExporter writes draws to w in some format.
+type DrawExporter interface {
+	Export(w io.Writer, draws []Draw) error
+}
+
+// CSVExporter is a DrawExporter that writes draws as CSV records, in the
+// format of Draw.MarshalCSV.
+type CSVExporter struct{}
+
+// Export writes draws to w with WriteDrawsCSV.
+func (CSVExporter) Export(w io.Writer, draws []Draw) error {
+	return WriteDrawsCSV(w, draws)
+}
+
+// JSONExporter is a DrawExporter that writes draws as a single JSON array.
+type JSONExporter struct{}
+
+// Export writes draws to w as a JSON array followed by a newline. No draws
+// are written as an empty array.
+func (JSONExporter) Export(w io.Writer, draws []Draw) error {
+	if draws == nil {
+		draws = []Draw{}
+	}
+	return json.NewEncoder(w).Encode(draws)
+}
+
+// JSONLExporter is a DrawExporter that writes draws as JSON Lines, that is one
+// JSON object per line.
+type JSONLExporter struct{}
+
+// Export writes each of draws to w as a JSON object on its own line.
+func (JSONLExporter) Export(w io.Writer, draws []Draw) error {
+	enc := json.NewEncoder(w)
+	for i := range draws {
+		if err := enc.Encode(&draws[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewDrawExporter returns the DrawExporter of format, which is one of "csv",
+// "json" and "jsonl".
+func NewDrawExporter(format string) (DrawExporter, error) {
+	switch format {
+	case "csv":
+		return CSVExporter{}, nil
+	case "json":
+		return JSONExporter{}, nil
+	case "jsonl":
+		return JSONLExporter{}, nil
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
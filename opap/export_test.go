@@ -0,0 +1,50 @@
+package opap
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewDrawExporter(t *testing.T) {
+	draws := []Draw{
+		{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1}},
+		{DrawTime: "28-12-2017T22:00:00", DrawNo: 1874, Results: []int{2, 12}},
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"csv", "24-12-2017T22:00:00,1873,40,13,1\n28-12-2017T22:00:00,1874,2,12\n"},
+		{"json", `[{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1]},{"drawTime":"28-12-2017T22:00:00","drawNo":1874,"results":[2,12]}]` + "\n"},
+		{"jsonl", `{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1]}` + "\n" + `{"drawTime":"28-12-2017T22:00:00","drawNo":1874,"results":[2,12]}` + "\n"},
+	}
+	for _, tt := range tests {
+		e, err := NewDrawExporter(tt.format)
+		if err != nil {
+			t.Errorf("NewDrawExporter(%q) returned err: %v", tt.format, err)
+			continue
+		}
+		var buf bytes.Buffer
+		if err := e.Export(&buf, draws); err != nil {
+			t.Errorf("%T.Export returned err: %v", e, err)
+			continue
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%T.Export \nhave: %q\nwant: %q", e, got, tt.want)
+		}
+	}
+
+	if _, err := NewDrawExporter("xml"); err == nil {
+		t.Error(`NewDrawExporter("xml") expected error`)
+	}
+}
+
+func TestJSONExporter_noDraws(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (JSONExporter{}).Export(&buf, nil); err != nil {
+		t.Fatal("JSONExporter.Export returned err:", err)
+	}
+	if got, want := buf.String(), "[]\n"; got != want {
+		t.Errorf("JSONExporter.Export(nil) = %q, want %q", got, want)
+	}
+}
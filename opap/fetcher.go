@@ -0,0 +1,216 @@
+package opap
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ConcurrentDrawFetcher is a DrawsService that distributes its calls across
+// the draws services of several clients in turn, to get past the limits of a
+// single client, such as its connections per host. The clients may point to
+// the same or to different endpoints. Each call is made in full by the draws
+// service of a single client, so a method that makes several requests, such as
+// ByDateRange, makes all of them with the same client.
+type ConcurrentDrawFetcher struct {
+	clients []*Client
+	next    uint32
+}
+
+var _ DrawsService = (*ConcurrentDrawFetcher)(nil)
+
+// NewConcurrentDrawFetcher returns a ConcurrentDrawFetcher that distributes
+// its calls across clients in round-robin order. It panics if clients is
+// empty.
+func NewConcurrentDrawFetcher(clients []*Client) *ConcurrentDrawFetcher {
+	if len(clients) == 0 {
+		panic("opap: NewConcurrentDrawFetcher called without clients")
+	}
+	cs := make([]*Client, len(clients))
+	copy(cs, clients)
+	return &ConcurrentDrawFetcher{clients: cs}
+}
+
+// draws returns the draws service of the client whose turn it is.
+func (f *ConcurrentDrawFetcher) draws() DrawsService {
+	n := atomic.AddUint32(&f.next, 1) - 1
+	return f.clients[n%uint32(len(f.clients))].Draws
+}
+
+// Latest calls Latest of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) Latest(g Game) (*Draw, *http.Response, error) {
+	return f.draws().Latest(g)
+}
+
+// LatestWithAge calls LatestWithAge of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) LatestWithAge(ctx context.Context, g Game) (*Draw, time.Duration, *http.Response, error) {
+	return f.draws().LatestWithAge(ctx, g)
+}
+
+// LatestIfNewer calls LatestIfNewer of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) LatestIfNewer(g Game, knownDrawNo int) (*Draw, bool, *http.Response, error) {
+	return f.draws().LatestIfNewer(g, knownDrawNo)
+}
+
+// ByNumber calls ByNumber of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) ByNumber(g Game, number int) (*Draw, *http.Response, error) {
+	return f.draws().ByNumber(g, number)
+}
+
+// ByNumberWithContext calls ByNumberWithContext of the draws service of the
+// next client.
+func (f *ConcurrentDrawFetcher) ByNumberWithContext(ctx context.Context, g Game, number int) (*Draw, *http.Response, error) {
+	return f.draws().ByNumberWithContext(ctx, g, number)
+}
+
+// ByNumberString calls ByNumberString of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) ByNumberString(g Game, number string) (*Draw, *http.Response, error) {
+	return f.draws().ByNumberString(g, number)
+}
+
+// ByDate calls ByDate of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) ByDate(g Game, day, month, year int) ([]Draw, *http.Response, error) {
+	return f.draws().ByDate(g, day, month, year)
+}
+
+// ByDateWithContext calls ByDateWithContext of the draws service of the next
+// client.
+func (f *ConcurrentDrawFetcher) ByDateWithContext(ctx context.Context, g Game, day, month, year int) ([]Draw, *http.Response, error) {
+	return f.draws().ByDateWithContext(ctx, g, day, month, year)
+}
+
+// ByDateRange calls ByDateRange of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) ByDateRange(g Game, start, end time.Time, opts ...DrawsOption) ([]Draw, []*http.Response, error) {
+	return f.draws().ByDateRange(g, start, end, opts...)
+}
+
+// ByDateRangeWithMetrics calls ByDateRangeWithMetrics of the draws service of
+// the next client.
+func (f *ConcurrentDrawFetcher) ByDateRangeWithMetrics(ctx context.Context, g Game, start, end time.Time) ([]Draw, []RequestMetric, error) {
+	return f.draws().ByDateRangeWithMetrics(ctx, g, start, end)
+}
+
+// ByDateRangeByWeekday calls ByDateRangeByWeekday of the draws service of the
+// next client.
+func (f *ConcurrentDrawFetcher) ByDateRangeByWeekday(ctx context.Context, g Game, start, end time.Time, weekdays ...time.Weekday) ([]Draw, error) {
+	return f.draws().ByDateRangeByWeekday(ctx, g, start, end, weekdays...)
+}
+
+// ByDateRangeSummary calls ByDateRangeSummary of the draws service of the next
+// client.
+func (f *ConcurrentDrawFetcher) ByDateRangeSummary(ctx context.Context, g Game, start, end time.Time) (map[time.Time]int, error) {
+	return f.draws().ByDateRangeSummary(ctx, g, start, end)
+}
+
+// ByDateRangeCSV calls ByDateRangeCSV of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) ByDateRangeCSV(ctx context.Context, g Game, start, end time.Time, w io.Writer) (int, error) {
+	return f.draws().ByDateRangeCSV(ctx, g, start, end, w)
+}
+
+// ByNumberRange calls ByNumberRange of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) ByNumberRange(ctx context.Context, g Game, from, to int) ([]Draw, error) {
+	return f.draws().ByNumberRange(ctx, g, from, to)
+}
+
+// FetchAllHistory calls FetchAllHistory of the draws service of the next
+// client.
+func (f *ConcurrentDrawFetcher) FetchAllHistory(ctx context.Context, g Game, progress func(fetched, total int)) ([]Draw, error) {
+	return f.draws().FetchAllHistory(ctx, g, progress)
+}
+
+// ByMonth calls ByMonth of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) ByMonth(g Game, month, year int) ([]Draw, error) {
+	return f.draws().ByMonth(g, month, year)
+}
+
+// MonthlyStats calls MonthlyStats of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) MonthlyStats(ctx context.Context, g Game, year int) ([]MonthlyDrawStats, error) {
+	return f.draws().MonthlyStats(ctx, g, year)
+}
+
+// ByDateRangeForAllGames calls ByDateRangeForAllGames of the draws service of
+// the next client.
+func (f *ConcurrentDrawFetcher) ByDateRangeForAllGames(ctx context.Context, start, end time.Time) (map[Game][]Draw, map[Game]error, error) {
+	return f.draws().ByDateRangeForAllGames(ctx, start, end)
+}
+
+// LatestAll calls LatestAll of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) LatestAll() (map[Game]*Draw, error) {
+	return f.draws().LatestAll()
+}
+
+// Watch calls Watch of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) Watch(ctx context.Context, g Game, interval time.Duration) (<-chan *Draw, <-chan error) {
+	return f.draws().Watch(ctx, g, interval)
+}
+
+// WatchAll calls WatchAll of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) WatchAll(ctx context.Context, interval time.Duration) (<-chan GameDraw, <-chan error) {
+	return f.draws().WatchAll(ctx, interval)
+}
+
+// PropoLatest calls PropoLatest of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) PropoLatest(g PropoGame) (*PropoDraw, *http.Response, error) {
+	return f.draws().PropoLatest(g)
+}
+
+// PropoLatestIfNewer calls PropoLatestIfNewer of the draws service of the next
+// client.
+func (f *ConcurrentDrawFetcher) PropoLatestIfNewer(g PropoGame, knownDrawNo int) (*PropoDraw, bool, *http.Response, error) {
+	return f.draws().PropoLatestIfNewer(g, knownDrawNo)
+}
+
+// PropoLatestN calls PropoLatestN of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) PropoLatestN(g PropoGame, n int) ([]PropoDraw, error) {
+	return f.draws().PropoLatestN(g, n)
+}
+
+// PropoByNumber calls PropoByNumber of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) PropoByNumber(g PropoGame, number int) (*PropoDraw, *http.Response, error) {
+	return f.draws().PropoByNumber(g, number)
+}
+
+// PropoByWeek calls PropoByWeek of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) PropoByWeek(g PropoGame, isoYear, isoWeek int) (*PropoDraw, *http.Response, error) {
+	return f.draws().PropoByWeek(g, isoYear, isoWeek)
+}
+
+// PropoByWeekRange calls PropoByWeekRange of the draws service of the next
+// client.
+func (f *ConcurrentDrawFetcher) PropoByWeekRange(g PropoGame, startYear, startWeek, endYear, endWeek int) ([]PropoDraw, error) {
+	return f.draws().PropoByWeekRange(g, startYear, startWeek, endYear, endWeek)
+}
+
+// PropoByDate calls PropoByDate of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) PropoByDate(g PropoGame, day, month, year int) ([]PropoDraw, *http.Response, error) {
+	return f.draws().PropoByDate(g, day, month, year)
+}
+
+// PropoByDateRange calls PropoByDateRange of the draws service of the next
+// client.
+func (f *ConcurrentDrawFetcher) PropoByDateRange(g PropoGame, start, end time.Time) ([]PropoDraw, []*http.Response, error) {
+	return f.draws().PropoByDateRange(g, start, end)
+}
+
+// PropoByMonth calls PropoByMonth of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) PropoByMonth(g PropoGame, month, year int) ([]PropoDraw, error) {
+	return f.draws().PropoByMonth(g, month, year)
+}
+
+// PropoLatestAll calls PropoLatestAll of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) PropoLatestAll() (map[PropoGame]*PropoDraw, error) {
+	return f.draws().PropoLatestAll()
+}
+
+// AllLatestByPropoGame calls AllLatestByPropoGame of the draws service of the
+// next client.
+func (f *ConcurrentDrawFetcher) AllLatestByPropoGame(ctx context.Context) (map[PropoGame]*PropoDraw, map[PropoGame]error, error) {
+	return f.draws().AllLatestByPropoGame(ctx)
+}
+
+// WatchPropo calls WatchPropo of the draws service of the next client.
+func (f *ConcurrentDrawFetcher) WatchPropo(ctx context.Context, g PropoGame, interval time.Duration) (<-chan *PropoDraw, <-chan error) {
+	return f.draws().WatchPropo(ctx, g, interval)
+}
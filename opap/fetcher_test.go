@@ -0,0 +1,45 @@
+package opap
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestConcurrentDrawFetcher(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var agents []string
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+		fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+	})
+
+	f := NewConcurrentDrawFetcher([]*Client{
+		NewClient(WithBaseURL(client.BaseURL), WithUserAgent("a")),
+		NewClient(WithBaseURL(client.BaseURL), WithUserAgent("b")),
+	})
+	for i := 0; i < 3; i++ {
+		d, _, err := f.Latest(Joker)
+		if err != nil {
+			t.Fatal("ConcurrentDrawFetcher.Latest returned err:", err)
+		}
+		if got, want := d.DrawNo, 1873; got != want {
+			t.Errorf("ConcurrentDrawFetcher.Latest DrawNo = %d, want %d", got, want)
+		}
+	}
+	if want := []string{"a", "b", "a"}; !reflect.DeepEqual(agents, want) {
+		t.Errorf("ConcurrentDrawFetcher used clients %v, want %v", agents, want)
+	}
+}
+
+func TestNewConcurrentDrawFetcher_noClients(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewConcurrentDrawFetcher(nil) did not panic")
+		}
+	}()
+	NewConcurrentDrawFetcher(nil)
+}
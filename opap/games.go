@@ -0,0 +1,203 @@
+package opap
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrUnknownGame is returned when a game is not one of the games known to
+// the package.
+var ErrUnknownGame = errors.New("unknown game")
+
+// ErrNoBallPool is returned by functions and methods that work on the pool of
+// balls of a game when they are given Propogoal, Penalties or Bowling, which
+// do not draw balls out of a pool.
+var ErrNoBallPool = errors.New("game does not draw balls")
+
+// Game is used to specify which OPAP game to bring results for.
+type Game string
+
+// Constants of all the game types the OPAP REST service supports except Propo.
+const (
+	// Kino draws 20 numbers out of a pool of 80 (1 to 80).
+	Kino Game = "kino"
+	// Lotto draws 6 numbers out of a pool of 49 (1 to 49) followed by a
+	// bonus number from the same pool.
+	Lotto Game = "lotto"
+	// Joker draws 5 numbers out of a pool of 45 (1 to 45) followed by the
+	// joker number out of a separate pool of 20 (1 to 20).
+	Joker Game = "joker"
+	// Proto draws a 7 digit number, one digit at a time out of a pool of 10
+	// (0 to 9).
+	Proto Game = "proto"
+	// Super3 draws a 3 digit number, one digit at a time out of a pool of 10
+	// (0 to 9).
+	Super3 Game = "super3"
+	// Extra5 draws 5 numbers out of a pool of 35 (1 to 35).
+	Extra5 Game = "extra5"
+	// Propogoal is the football goals game. Its results are the goals of
+	// the matches of the coupon rather than balls drawn out of a pool.
+	Propogoal Game = "propogoal"
+	// Penalties is the virtual penalty shoot-out game. Its results are
+	// outcomes of the shoot-out rather than balls drawn out of a pool.
+	Penalties Game = "penalties"
+	// Bowling is the virtual bowling game. Its results are outcomes of the
+	// game rather than balls drawn out of a pool.
+	Bowling Game = "bowling"
+	// Powerspin draws 1 number out of the 24 numbers (1 to 24) of a
+	// spinning wheel.
+	Powerspin Game = "powerspin"
+)
+
+// SupportedGames lists all the games of the OPAP REST service, except Propo,
+// in alphabetical order.
+var SupportedGames = []Game{
+	Bowling,
+	Extra5,
+	Joker,
+	Kino,
+	Lotto,
+	Penalties,
+	Powerspin,
+	Propogoal,
+	Proto,
+	Super3,
+}
+
+// AllGames returns all the games of the OPAP REST service, except Propo, in
+// alphabetical order. Unlike SupportedGames, the returned slice is a copy and
+// may be modified.
+func AllGames() []Game {
+	games := make([]Game, len(SupportedGames))
+	copy(games, SupportedGames)
+	return games
+}
+
+// String returns the display name of the game, which is its name in upper
+// case, for example "JOKER". Use string(g) for the name the OPAP REST service
+// expects.
+func (g Game) String() string {
+	return strings.ToUpper(string(g))
+}
+
+// ResultCount returns the number of results of a draw of game g, including
+// the joker number of Joker and the bonus number of Lotto. It returns 0 for
+// Propogoal, Penalties and Bowling, whose results are not balls drawn out of
+// a pool, and for unknown games.
+func (g Game) ResultCount() int {
+	info, err := InfoFor(g)
+	if err != nil {
+		return 0
+	}
+	return info.DrawCount + info.BonusCount
+}
+
+// GameInfo describes the rules of a game.
+type GameInfo struct {
+	Name Game
+	// PoolSize is the number of balls that the main numbers are drawn out
+	// of, and DrawCount is how many main numbers are drawn. FirstBall is the
+	// lowest ball of the pool, which is 1 except for the digit games Proto
+	// and Super3, whose pool starts at 0.
+	PoolSize  int
+	DrawCount int
+	FirstBall int
+	// BonusPoolSize is the number of balls that the bonus numbers, such as
+	// the joker number of Joker, are drawn out of, starting at 1, and
+	// BonusCount is how many bonus numbers are drawn.
+	BonusPoolSize int
+	BonusCount    int
+	// DrawsPerDay is how many draws take place each day. It is 0 for games
+	// that do not draw every day, as well as for games whose schedule the
+	// package does not record.
+	DrawsPerDay int
+}
+
+// gameInfo holds the GameInfo of each of the SupportedGames. Propogoal,
+// Penalties and Bowling do not draw balls out of a pool, so only their name
+// is set.
+var gameInfo = map[Game]GameInfo{
+	Kino:      {Name: Kino, PoolSize: 80, DrawCount: 20, FirstBall: 1},
+	Lotto:     {Name: Lotto, PoolSize: 49, DrawCount: 6, FirstBall: 1, BonusPoolSize: 49, BonusCount: 1},
+	Joker:     {Name: Joker, PoolSize: 45, DrawCount: 5, FirstBall: 1, BonusPoolSize: 20, BonusCount: 1},
+	Proto:     {Name: Proto, PoolSize: 10, DrawCount: 7},
+	Super3:    {Name: Super3, PoolSize: 10, DrawCount: 3},
+	Extra5:    {Name: Extra5, PoolSize: 35, DrawCount: 5, FirstBall: 1},
+	Propogoal: {Name: Propogoal},
+	Penalties: {Name: Penalties},
+	Bowling:   {Name: Bowling},
+	Powerspin: {Name: Powerspin, PoolSize: 24, DrawCount: 1, FirstBall: 1},
+}
+
+// InfoFor returns the GameInfo of game g. It returns ErrUnknownGame if g is
+// not one of the SupportedGames.
+func InfoFor(g Game) (GameInfo, error) {
+	info, ok := gameInfo[g]
+	if !ok {
+		return GameInfo{}, ErrUnknownGame
+	}
+	return info, nil
+}
+
+// lastBall returns the highest ball of the pool of the main numbers.
+func (info GameInfo) lastBall() int {
+	return info.FirstBall + info.PoolSize - 1
+}
+
+// sumRange returns the smallest and the largest possible sum of the main
+// numbers. The balls of a pool are drawn without replacement, so the sums are
+// those of the lowest and the highest DrawCount balls, except for the digit
+// games Proto and Super3, whose digits are drawn independently and can
+// repeat.
+func (info GameInfo) sumRange() (min, max int) {
+	if info.FirstBall == 0 {
+		return 0, info.DrawCount * info.lastBall()
+	}
+	n := info.DrawCount
+	return n * (n + 1) / 2, n*info.lastBall() - n*(n-1)/2
+}
+
+// PropoGame is used to specify which Propo game to bring results for. Its
+// value is not exported, so the only valid PropoGames are PropoSun, PropoSat
+// and PropoWed, and a misspelled game name cannot be turned into a PropoGame
+// by mistake. The zero PropoGame is not a valid game, and the draws service
+// methods return ErrUnknownGame for it without making a request.
+type PropoGame struct {
+	value string
+}
+
+// The Propo game types.
+var (
+	PropoSun = PropoGame{"proposun"}
+	PropoSat = PropoGame{"proposat"}
+	PropoWed = PropoGame{"propowed"}
+)
+
+// Value returns the name of Propo game g as the OPAP REST service knows it,
+// for example "proposat".
+func (g PropoGame) Value() string {
+	return g.value
+}
+
+// String returns the same as Value so that g formats as its name.
+func (g PropoGame) String() string {
+	return g.value
+}
+
+// AllPropoGames returns all the Propo games in alphabetical order.
+func AllPropoGames() []PropoGame {
+	return []PropoGame{PropoSat, PropoSun, PropoWed}
+}
+
+// propoMatches is the number of matches on the coupon of every Propo game.
+const propoMatches = 14
+
+// DrawCount returns the number of matches that are drawn in Propo game g. It
+// returns ErrUnknownGame if g is not one of the Propo game constants.
+func (g PropoGame) DrawCount() (int, error) {
+	switch g {
+	case PropoSun, PropoSat, PropoWed:
+		return propoMatches, nil
+	}
+	return 0, ErrUnknownGame
+}
@@ -0,0 +1,116 @@
+package opap
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSupportedGames(t *testing.T) {
+	if got, want := len(SupportedGames), 10; got != want {
+		t.Errorf("len(SupportedGames) = %d, want %d", got, want)
+	}
+
+	sorted := sort.SliceIsSorted(SupportedGames, func(i, j int) bool {
+		return SupportedGames[i] < SupportedGames[j]
+	})
+	if !sorted {
+		t.Errorf("SupportedGames = %q, want alphabetical order", SupportedGames)
+	}
+}
+
+func TestAllGames(t *testing.T) {
+	all := AllGames()
+	for _, g := range []Game{Kino, Lotto, Joker, Proto, Super3, Extra5, Propogoal, Penalties, Bowling, Powerspin} {
+		found := false
+		for _, a := range all {
+			if a == g {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("AllGames() = %v, missing %s", all, string(g))
+		}
+	}
+
+	all[0] = "foo"
+	if SupportedGames[0] == "foo" {
+		t.Error("modifying the slice returned by AllGames modified SupportedGames")
+	}
+}
+
+func TestAllPropoGames(t *testing.T) {
+	want := []PropoGame{PropoSat, PropoSun, PropoWed}
+	if got := AllPropoGames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllPropoGames() = %q, want %q", got, want)
+	}
+}
+
+func TestGame_String(t *testing.T) {
+	if got, want := Joker.String(), "JOKER"; got != want {
+		t.Errorf("Joker.String() = %q, want %q", got, want)
+	}
+	if got, want := Super3.String(), "SUPER3"; got != want {
+		t.Errorf("Super3.String() = %q, want %q", got, want)
+	}
+}
+
+func TestGame_ResultCount(t *testing.T) {
+	for _, g := range AllGames() {
+		n := g.ResultCount()
+		switch g {
+		case Propogoal, Penalties, Bowling:
+			if n != 0 {
+				t.Errorf("%s.ResultCount() = %d, want 0", string(g), n)
+			}
+		default:
+			if n <= 0 {
+				t.Errorf("%s.ResultCount() = %d, want a positive count", string(g), n)
+			}
+		}
+	}
+	if got, want := Joker.ResultCount(), 6; got != want {
+		t.Errorf("Joker.ResultCount() = %d, want %d", got, want)
+	}
+}
+
+func TestInfoFor(t *testing.T) {
+	for _, g := range AllGames() {
+		info, err := InfoFor(g)
+		if err != nil {
+			t.Errorf("InfoFor(%s) returned err: %v", string(g), err)
+		}
+		if info.Name != g {
+			t.Errorf("InfoFor(%s).Name = %s", string(g), string(info.Name))
+		}
+	}
+
+	want := GameInfo{Name: Joker, PoolSize: 45, DrawCount: 5, FirstBall: 1, BonusPoolSize: 20, BonusCount: 1}
+	if got, _ := InfoFor(Joker); got != want {
+		t.Errorf("InfoFor(joker) \nhave: %#v\nwant: %#v", got, want)
+	}
+
+	if _, err := InfoFor("foo"); err != ErrUnknownGame {
+		t.Errorf("InfoFor(foo) err = %v, want %v", err, ErrUnknownGame)
+	}
+}
+
+func TestPropoGame_DrawCount(t *testing.T) {
+	for _, g := range []PropoGame{PropoSun, PropoSat, PropoWed} {
+		n, err := g.DrawCount()
+		if err != nil {
+			t.Errorf("PropoGame(%q).DrawCount() returned err: %v", g, err)
+		}
+		if got, want := n, 14; got != want {
+			t.Errorf("PropoGame(%q).DrawCount() = %d, want %d", g, got, want)
+		}
+	}
+}
+
+func TestPropoGame_DrawCount_unknownGame(t *testing.T) {
+	game := PropoGame{"propofoo"}
+	if _, err := game.DrawCount(); err != ErrUnknownGame {
+		t.Errorf("PropoGame(%q).DrawCount() err = %v, want %v", game, err, ErrUnknownGame)
+	}
+}
@@ -0,0 +1,42 @@
+package opap
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ResponseHeaders gives access to the headers of a response of the OPAP REST
+// service that are of interest to callers. It is returned by HeadersFrom.
+type ResponseHeaders struct {
+	h http.Header
+}
+
+// HeadersFrom returns the ResponseHeaders of resp, such as one returned by a
+// method of the draws service. A nil resp has no headers.
+func HeadersFrom(resp *http.Response) ResponseHeaders {
+	if resp == nil {
+		return ResponseHeaders{}
+	}
+	return ResponseHeaders{h: resp.Header}
+}
+
+// ContentType returns the Content-Type header.
+func (h ResponseHeaders) ContentType() string {
+	return h.h.Get("Content-Type")
+}
+
+// XRequestID returns the X-Request-Id header.
+func (h ResponseHeaders) XRequestID() string {
+	return h.h.Get("X-Request-Id")
+}
+
+// RateLimitRemaining returns the number of requests that the X-RateLimit-
+// Remaining header reports are left. The boolean result is false if the
+// header is missing or is not a number.
+func (h ResponseHeaders) RateLimitRemaining() (int, bool) {
+	n, err := strconv.Atoi(h.h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
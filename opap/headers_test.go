@@ -0,0 +1,52 @@
+package opap
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestHeadersFrom(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "abc123")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+	})
+
+	_, resp, err := client.Draws.Latest(Joker)
+	if err != nil {
+		t.Fatal("client.Draws.Latest returned err:", err)
+	}
+	h := HeadersFrom(resp)
+	if got, want := h.ContentType(), "application/json"; got != want {
+		t.Errorf("ContentType() = %q, want %q", got, want)
+	}
+	if got, want := h.XRequestID(), "abc123"; got != want {
+		t.Errorf("XRequestID() = %q, want %q", got, want)
+	}
+	if got, ok := h.RateLimitRemaining(); got != 42 || !ok {
+		t.Errorf("RateLimitRemaining() = %d, %v, want 42, true", got, ok)
+	}
+}
+
+func TestHeadersFrom_missing(t *testing.T) {
+	for _, resp := range []*http.Response{
+		nil,
+		{Header: http.Header{"X-Ratelimit-Remaining": {"foo"}}},
+	} {
+		h := HeadersFrom(resp)
+		if got := h.ContentType(); got != "" {
+			t.Errorf("ContentType() = %q, want empty", got)
+		}
+		if got := h.XRequestID(); got != "" {
+			t.Errorf("XRequestID() = %q, want empty", got)
+		}
+		if got, ok := h.RateLimitRemaining(); got != 0 || ok {
+			t.Errorf("RateLimitRemaining() = %d, %v, want 0, false", got, ok)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package opap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// GameError records the error of fetching the draw of a single game. Game is
+// the name of the game as the OPAP REST service knows it, for example
+// "joker" or "proposat".
+type GameError struct {
+	Game string
+	Err  error
+}
+
+func (e GameError) Error() string {
+	return e.Game + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error of the game.
+func (e GameError) Unwrap() error {
+	return e.Err
+}
+
+// GamesError is returned by LatestAll and PropoLatestAll when the draws of
+// some of the games could not be fetched. It lists the failed games in
+// alphabetical order.
+type GamesError []GameError
+
+func (e GamesError) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "fetching draws of %d games failed:", len(e))
+	for i, ge := range e {
+		if i > 0 {
+			buf.WriteString(";")
+		}
+		fmt.Fprintf(&buf, " %s: %v", ge.Game, ge.Err)
+	}
+	return buf.String()
+}
+
+// LatestAll returns the latest draw of each of the SupportedGames, fetching
+// all the games at the same time. If some of the games cannot be fetched, it
+// returns the draws of the rest of the games along with a GamesError that
+// lists the failed games. It returns only after every request has finished,
+// so no goroutines are left behind even when the HTTP client times out.
+func (s *drawsService) LatestAll() (map[Game]*Draw, error) {
+	var (
+		mu    sync.Mutex
+		draws = make(map[Game]*Draw)
+		errs  GamesError
+	)
+	games := AllGames()
+	allGames(len(games), func(i int) {
+		g := games[i]
+		d, _, err := s.Latest(g)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, GameError{Game: string(g), Err: err})
+			return
+		}
+		draws[g] = d
+	})
+	if len(errs) != 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Game < errs[j].Game })
+		return draws, errs
+	}
+	return draws, nil
+}
+
+// PropoLatestAll is like LatestAll for the Propo games.
+func (s *drawsService) PropoLatestAll() (map[PropoGame]*PropoDraw, error) {
+	draws, gameErrs, _ := s.AllLatestByPropoGame(context.Background())
+	if len(gameErrs) == 0 {
+		return draws, nil
+	}
+	var errs GamesError
+	for g, err := range gameErrs {
+		errs = append(errs, GameError{Game: g.Value(), Err: err})
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Game < errs[j].Game })
+	return draws, errs
+}
+
+// AllLatestByPropoGame returns the latest draw of each of the Propo games,
+// fetching all the games at the same time, along with the error of each game
+// that could not be fetched. The requests are cancelled when ctx is done, in
+// which case the error of ctx is returned as well. Like LatestAll, it returns
+// only after every request has finished.
+func (s *drawsService) AllLatestByPropoGame(ctx context.Context) (map[PropoGame]*PropoDraw, map[PropoGame]error, error) {
+	var (
+		mu    sync.Mutex
+		draws = make(map[PropoGame]*PropoDraw)
+		errs  = make(map[PropoGame]error)
+	)
+	games := AllPropoGames()
+	allGames(len(games), func(i int) {
+		g := games[i]
+		d, _, err := s.propoLatest(ctx, g)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[g] = err
+			return
+		}
+		draws[g] = d
+	})
+	return draws, errs, ctx.Err()
+}
+
+// allGames calls fetch for each of n games at the same time and waits for
+// all the calls to return.
+func allGames(n int, fetch func(i int)) {
+	concurrently(n, n, func(i int) error {
+		fetch(i)
+		return nil
+	})
+}
@@ -0,0 +1,152 @@
+package opap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDrawService_LatestAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for i, g := range SupportedGames {
+		drawNo := 1000 + i
+		mux.HandleFunc("/"+defaultDrawsEndpoint+"/"+string(g)+"/last.json", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			fmt.Fprintf(w, `{"draw":{"drawNo":%d}}`, drawNo)
+		})
+	}
+
+	got, err := client.Draws.LatestAll()
+	if err != nil {
+		t.Fatal("client.Draws.LatestAll returned err:", err)
+	}
+	want := make(map[Game]*Draw)
+	for i, g := range SupportedGames {
+		want[g] = &Draw{DrawNo: 1000 + i}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.LatestAll() \nhave: %v\nwant: %v", got, want)
+	}
+}
+
+func TestDrawService_LatestAll_partialFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, g := range SupportedGames {
+		g := g
+		mux.HandleFunc("/"+defaultDrawsEndpoint+"/"+string(g)+"/last.json", func(w http.ResponseWriter, r *http.Request) {
+			if g == Kino || g == Joker {
+				http.Error(w, "something broke", 500)
+				return
+			}
+			fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+		})
+	}
+
+	got, err := client.Draws.LatestAll()
+	gamesErr, ok := err.(GamesError)
+	if !ok {
+		t.Fatalf("client.Draws.LatestAll err = %v, want GamesError", err)
+	}
+	var failed []string
+	for _, ge := range gamesErr {
+		failed = append(failed, ge.Game)
+		testErrorResponse(t, ge.Err, 500)
+	}
+	if want := []string{"joker", "kino"}; !reflect.DeepEqual(failed, want) {
+		t.Errorf("GamesError games = %q, want %q", failed, want)
+	}
+	if got, want := len(got), len(SupportedGames)-2; got != want {
+		t.Errorf("client.Draws.LatestAll returned %d draws, want %d", got, want)
+	}
+	if _, ok := got[Joker]; ok {
+		t.Error("client.Draws.LatestAll returned a draw for a failed game")
+	}
+}
+
+func TestDrawService_PropoLatestAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, g := range AllPropoGames() {
+		g := g
+		mux.HandleFunc("/"+defaultDrawsEndpoint+"/"+g.Value()+"/last.json", func(w http.ResponseWriter, r *http.Request) {
+			if g == PropoWed {
+				http.Error(w, "something broke", 500)
+				return
+			}
+			fmt.Fprint(w, `{"draw":{"drawNo":201751,"results":["1","X"]}}`)
+		})
+	}
+
+	got, err := client.Draws.PropoLatestAll()
+	if _, ok := err.(GamesError); !ok {
+		t.Errorf("client.Draws.PropoLatestAll err = %v, want GamesError", err)
+	}
+	d := &PropoDraw{DrawNo: 201751, Results: []string{"1", "X"}}
+	want := map[PropoGame]*PropoDraw{PropoSat: d, PropoSun: d}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.PropoLatestAll() \nhave: %v\nwant: %v", got, want)
+	}
+}
+
+func TestDrawService_AllLatestByPropoGame(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, g := range AllPropoGames() {
+		g := g
+		mux.HandleFunc("/"+defaultDrawsEndpoint+"/"+g.Value()+"/last.json", func(w http.ResponseWriter, r *http.Request) {
+			if g == PropoWed {
+				http.Error(w, "something broke", 500)
+				return
+			}
+			fmt.Fprint(w, `{"draw":{"drawNo":201751,"results":["1","X"]}}`)
+		})
+	}
+
+	got, errs, err := client.Draws.AllLatestByPropoGame(context.Background())
+	if err != nil {
+		t.Fatal("client.Draws.AllLatestByPropoGame returned err:", err)
+	}
+	d := &PropoDraw{DrawNo: 201751, Results: []string{"1", "X"}}
+	want := map[PropoGame]*PropoDraw{PropoSat: d, PropoSun: d}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.AllLatestByPropoGame() \nhave: %v\nwant: %v", got, want)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("client.Draws.AllLatestByPropoGame returned %d game errors, want 1", len(errs))
+	}
+	testErrorResponse(t, errs[PropoWed], 500)
+}
+
+func TestDrawService_AllLatestByPropoGame_cancelled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	got, errs, err := client.Draws.AllLatestByPropoGame(ctx)
+	if err != context.Canceled {
+		t.Errorf("client.Draws.AllLatestByPropoGame err = %v, want %v", err, context.Canceled)
+	}
+	if len(got) != 0 || len(errs) != len(AllPropoGames()) {
+		t.Errorf("client.Draws.AllLatestByPropoGame with cancelled ctx returned %d draws and %d errors, want 0 and %d", len(got), len(errs), len(AllPropoGames()))
+	}
+}
+
+func TestGamesError_Error(t *testing.T) {
+	err := GamesError{
+		{Game: "joker", Err: fmt.Errorf("foo")},
+		{Game: "kino", Err: fmt.Errorf("bar")},
+	}
+	want := "fetching draws of 2 games failed: joker: foo; kino: bar"
+	if got := err.Error(); got != want {
+		t.Errorf("GamesError.Error() = %q, want %q", got, want)
+	}
+}
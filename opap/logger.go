@@ -0,0 +1,52 @@
+package opap
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Logger is used by the client to report each request it made that was
+// answered, as set with the WithLogger option. Log can be called by several
+// goroutines at the same time.
+type Logger interface {
+	Log(method, url string, statusCode int, elapsed time.Duration)
+}
+
+// Warner is implemented by Loggers that are also told of conditions the
+// caller should know about, such as a request for an unusually long history
+// of draws. The client warns its Logger only if it implements Warner.
+type Warner interface {
+	Warn(msg string)
+}
+
+// StdLogger is a Logger that writes a line for each request to an io.Writer,
+// for example:
+//
+//	GET http://applications.opap.gr/DrawsRestServices/joker/last.json 200 85ms
+type StdLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStdLogger returns a StdLogger that writes to w.
+func NewStdLogger(w io.Writer) *StdLogger {
+	return &StdLogger{w: w}
+}
+
+// Log writes a line with the method, the URL, the status code and the
+// duration of a request. Errors writing the line are ignored.
+func (l *StdLogger) Log(method, url string, statusCode int, elapsed time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.w, "%s %s %d %v\n", method, url, statusCode, elapsed.Round(time.Millisecond))
+}
+
+// Warn writes a line with msg prefixed by "WARN". Errors writing the line are
+// ignored.
+func (l *StdLogger) Warn(msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.w, "WARN %s\n", msg)
+}
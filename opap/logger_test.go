@@ -0,0 +1,87 @@
+package opap
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type logEntry struct {
+	method, url string
+	statusCode  int
+}
+
+// recordingLogger is a Logger that records the requests it is told of.
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) Log(method, url string, statusCode int, elapsed time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{method, url, statusCode})
+}
+
+// warningLogger is a recordingLogger that also records the warnings it is
+// given.
+type warningLogger struct {
+	recordingLogger
+	warnings []string
+}
+
+func (l *warningLogger) Warn(msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warnings = append(l.warnings, msg)
+}
+
+func TestWithLogger(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/1873.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	l := new(recordingLogger)
+	c := NewClient(WithBaseURL(client.BaseURL), WithLogger(l))
+	if _, _, err := c.Draws.Latest(Joker); err != nil {
+		t.Fatal("Latest returned err:", err)
+	}
+	_, _, err := c.Draws.ByNumber(Joker, 1873)
+	testErrorResponse(t, err, 500)
+
+	want := []logEntry{
+		{"GET", server.URL + "/" + defaultDrawsEndpoint + "/joker/last.json", 200},
+		{"GET", server.URL + "/" + defaultDrawsEndpoint + "/joker/1873.json", 500},
+	}
+	if len(l.entries) != len(want) {
+		t.Fatalf("logger called %d times, want %d: %v", len(l.entries), len(want), l.entries)
+	}
+	for i := range want {
+		if l.entries[i] != want[i] {
+			t.Errorf("log entry %d = %v, want %v", i, l.entries[i], want[i])
+		}
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStdLogger(&buf)
+	l.Log("GET", "http://example.com/joker/last.json", 200, 1500*time.Microsecond)
+
+	l.Warn("fetching the full history of KINO: 900000 draws")
+
+	want := "GET http://example.com/joker/last.json 200 2ms\n" +
+		"WARN fetching the full history of KINO: 900000 draws\n"
+	if got := buf.String(); got != want {
+		t.Errorf("StdLogger wrote %q, want %q", got, want)
+	}
+}
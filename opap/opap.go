@@ -2,12 +2,16 @@ package opap
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 )
 
 const (
@@ -19,31 +23,103 @@ const (
 type Client struct {
 	client *http.Client
 
+	// retry is set by WithRetry and wraps the transport of client once all
+	// options have been applied.
+	retry *RetryTransport
+
+	// timeout is set by WithTimeout and overrides the timeout of client once
+	// all options have been applied.
+	timeout time.Duration
+
+	// cache is set by WithCache and wraps the transport of client, outside
+	// of any retries, once all options have been applied.
+	cache *cacheTransport
+
+	// logger is set by WithLogger and is told of every answered request.
+	logger Logger
+
+	// apiKey is set by WithAPIKey and is sent as the api_key query
+	// parameter of every request.
+	apiKey string
+
 	BaseURL *url.URL
 
-	Draws *drawsService
-}
+	// UserAgent, if set, is sent as the User-Agent header of every request.
+	UserAgent string
 
-// NewClient returns a new OPAP API client.
-func NewClient(httpClient *http.Client) *Client {
-	if httpClient == nil {
-		httpClient = http.DefaultClient
-	}
+	Draws DrawsService
+}
 
+// NewClient returns a new OPAP API client configured by opts. Without any
+// options the client uses http.DefaultClient and the default base URL and
+// draws endpoint. Nil options are ignored, so NewClient(nil) is the same as
+// NewClient().
+func NewClient(opts ...ClientOption) *Client {
 	baseURL, _ := url.Parse(defaultBaseURL)
 
 	c := &Client{
-		client:  httpClient,
+		client:  http.DefaultClient,
 		BaseURL: baseURL,
 	}
 
 	c.Draws = &drawsService{
-		client:   c,
-		Endpoint: defaultDrawsEndpoint,
+		client:         c,
+		Endpoint:       defaultDrawsEndpoint,
+		maxConcurrency: defaultMaxConcurrency,
+		timeParser:     DefaultDrawTimeParser,
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+
+	if c.retry != nil || c.cache != nil || c.timeout > 0 {
+		// Copy the http.Client so that a client passed with WithHTTPClient,
+		// or http.DefaultClient, is left untouched.
+		hc := *c.client
+		if c.retry != nil {
+			c.retry.Base = hc.Transport
+			hc.Transport = c.retry
+		}
+		if c.cache != nil {
+			c.cache.base = hc.Transport
+			if c.cache.base == nil {
+				c.cache.base = http.DefaultTransport
+			}
+			hc.Transport = c.cache
+		}
+		if c.timeout > 0 {
+			hc.Timeout = c.timeout
+		}
+		c.client = &hc
 	}
 	return c
 }
 
+// NewClientWithBaseURL returns a new OPAP API client that sends its requests
+// to rawURL instead of the default base URL. It returns an error if rawURL
+// cannot be parsed.
+func NewClientWithBaseURL(rawURL string, httpClient *http.Client) (*Client, error) {
+	c := NewClient(WithHTTPClient(httpClient))
+	if err := c.SetBaseURL(rawURL); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SetBaseURL parses rawURL and sets it as the BaseURL of the client. It
+// returns an error and leaves BaseURL unchanged if rawURL cannot be parsed.
+func (c *Client) SetBaseURL(rawURL string) error {
+	baseURL, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	c.BaseURL = baseURL
+	return nil
+}
+
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
 // in which case it is resolved relative to the BaseURL of the Client.
 // Relative URLs should always be specified without a preceding slash.
@@ -56,24 +132,39 @@ func (c *Client) NewRequest(method, urlStr string, body io.Reader) (*http.Reques
 	}
 
 	u := c.BaseURL.ResolveReference(rel)
+	if c.apiKey != "" {
+		q := u.Query()
+		q.Add(apiKeyParam, c.apiKey)
+		u.RawQuery = q.Encode()
+	}
 
 	req, err := http.NewRequest(method, u.String(), body)
 	if err != nil {
 		return nil, err
 	}
 
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
 	return req, nil
 }
 
 // Do sends an API request and returns the API response. The API response is
-// JSON decoded and stored in the value pointed to by v.
+// JSON decoded and stored in the value pointed to by v. If the client has a
+// Logger, it is told of the request once the response arrives.
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
+	start := time.Now()
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if c.logger != nil {
+		c.logger.Log(req.Method, maskAPIKey(req.URL), resp.StatusCode, time.Since(start))
+	}
+
 	if err := checkResponse(resp); err != nil {
 		return resp, err
 	}
@@ -90,6 +181,22 @@ func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
 	return resp, nil
 }
 
+// apiKeyParam is the query parameter that WithAPIKey sends the API key as.
+const apiKeyParam = "api_key"
+
+// maskAPIKey returns u as a string with the value of its API key, if any,
+// masked so that the key does not end up in logs.
+func maskAPIKey(u *url.URL) string {
+	q := u.Query()
+	if _, ok := q[apiKeyParam]; !ok {
+		return u.String()
+	}
+	q.Set(apiKeyParam, "xxx")
+	masked := *u
+	masked.RawQuery = q.Encode()
+	return masked.String()
+}
+
 func checkResponse(r *http.Response) error {
 	if c := r.StatusCode; 200 <= c && c <= 299 {
 		return nil
@@ -100,44 +207,96 @@ func checkResponse(r *http.Response) error {
 		return fmt.Errorf("reading response body: %v", err)
 	}
 
-	return fmt.Errorf("%v %v: %d %s", r.Request.Method, r.Request.URL, r.StatusCode, string(data))
+	return &ErrorResponse{
+		Method:     r.Request.Method,
+		URL:        r.Request.URL,
+		StatusCode: r.StatusCode,
+		Body:       string(data),
+	}
+}
+
+// ErrorResponse reports an error caused by an API request that was answered
+// with a status code outside of the 2xx range.
+type ErrorResponse struct {
+	Method     string
+	URL        *url.URL
+	StatusCode int
+	Body       string
+}
+
+func (e *ErrorResponse) Error() string {
+	return fmt.Sprintf("%v %v: %d %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err is, or wraps, an *ErrorResponse with status
+// code 404.
+func IsNotFound(err error) bool {
+	var e *ErrorResponse
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+// IsServerError reports whether err is, or wraps, an *ErrorResponse with a
+// status code in the 5xx range.
+func IsServerError(err error) bool {
+	var e *ErrorResponse
+	return errors.As(err, &e) && 500 <= e.StatusCode && e.StatusCode <= 599
 }
 
 func (c *Client) get(url string, result interface{}) (*http.Response, error) {
+	return c.getContext(context.Background(), url, result)
+}
+
+func (c *Client) getContext(ctx context.Context, url string, result interface{}) (*http.Response, error) {
 	req, err := c.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.Do(req, result)
+	return c.Do(req.WithContext(ctx), result)
 }
 
-// Game is used to specify which OPAP game to bring results for.
-type Game string
-
-// Constants of all the game types the OPAP REST service supports except Propo.
-const (
-	Kino      Game = "kino"
-	Lotto          = "lotto"
-	Joker          = "joker"
-	Proto          = "proto"
-	Super3         = "super3"
-	Extra5         = "extra5"
-	Propogoal      = "propogoal"
-	Penalties      = "penalties"
-	Bowling        = "bowling"
-	Pοwerspin      = "pοwerspin"
-)
-
-// PropoGame is used to specify which Propo game to bring results for.
-type PropoGame string
+// DrawsService is the interface of the draws service of the Client. It is
+// satisfied by the service that NewClient creates and can be implemented by
+// fakes, such as opaptest.MockDrawsService, in tests of code that uses the
+// Client.
+type DrawsService interface {
+	Latest(g Game) (*Draw, *http.Response, error)
+	LatestWithAge(ctx context.Context, g Game) (*Draw, time.Duration, *http.Response, error)
+	LatestIfNewer(g Game, knownDrawNo int) (*Draw, bool, *http.Response, error)
+	ByNumber(g Game, number int) (*Draw, *http.Response, error)
+	ByNumberWithContext(ctx context.Context, g Game, number int) (*Draw, *http.Response, error)
+	ByNumberString(g Game, number string) (*Draw, *http.Response, error)
+	ByDate(g Game, day, month, year int) ([]Draw, *http.Response, error)
+	ByDateWithContext(ctx context.Context, g Game, day, month, year int) ([]Draw, *http.Response, error)
+	ByDateRange(g Game, start, end time.Time, opts ...DrawsOption) ([]Draw, []*http.Response, error)
+	ByDateRangeWithMetrics(ctx context.Context, g Game, start, end time.Time) ([]Draw, []RequestMetric, error)
+	ByDateRangeByWeekday(ctx context.Context, g Game, start, end time.Time, weekdays ...time.Weekday) ([]Draw, error)
+	ByDateRangeSummary(ctx context.Context, g Game, start, end time.Time) (map[time.Time]int, error)
+	ByDateRangeCSV(ctx context.Context, g Game, start, end time.Time, w io.Writer) (int, error)
+	ByNumberRange(ctx context.Context, g Game, from, to int) ([]Draw, error)
+	FetchAllHistory(ctx context.Context, g Game, progress func(fetched, total int)) ([]Draw, error)
+	ByMonth(g Game, month, year int) ([]Draw, error)
+	MonthlyStats(ctx context.Context, g Game, year int) ([]MonthlyDrawStats, error)
+	ByDateRangeForAllGames(ctx context.Context, start, end time.Time) (map[Game][]Draw, map[Game]error, error)
+	LatestAll() (map[Game]*Draw, error)
+	Watch(ctx context.Context, g Game, interval time.Duration) (<-chan *Draw, <-chan error)
+	WatchAll(ctx context.Context, interval time.Duration) (<-chan GameDraw, <-chan error)
+
+	PropoLatest(g PropoGame) (*PropoDraw, *http.Response, error)
+	PropoLatestIfNewer(g PropoGame, knownDrawNo int) (*PropoDraw, bool, *http.Response, error)
+	PropoLatestN(g PropoGame, n int) ([]PropoDraw, error)
+	PropoByNumber(g PropoGame, number int) (*PropoDraw, *http.Response, error)
+	PropoByWeek(g PropoGame, isoYear, isoWeek int) (*PropoDraw, *http.Response, error)
+	PropoByWeekRange(g PropoGame, startYear, startWeek, endYear, endWeek int) ([]PropoDraw, error)
+	PropoByDate(g PropoGame, day, month, year int) ([]PropoDraw, *http.Response, error)
+	PropoByDateRange(g PropoGame, start, end time.Time) ([]PropoDraw, []*http.Response, error)
+	PropoByMonth(g PropoGame, month, year int) ([]PropoDraw, error)
+	PropoLatestAll() (map[PropoGame]*PropoDraw, error)
+	AllLatestByPropoGame(ctx context.Context) (map[PropoGame]*PropoDraw, map[PropoGame]error, error)
+	WatchPropo(ctx context.Context, g PropoGame, interval time.Duration) (<-chan *PropoDraw, <-chan error)
+}
 
-// The Propo game types.
-const (
-	PropoSun PropoGame = "proposun"
-	PropoSat           = "proposat"
-	PropoWed           = "propowed"
-)
+var _ DrawsService = (*drawsService)(nil)
 
 // drawsService handles communication with the DrawsRestServices endpoint.
 //
@@ -145,6 +304,14 @@ const (
 type drawsService struct {
 	client   *Client
 	Endpoint string
+
+	// maxConcurrency limits the requests that methods which fetch several
+	// draws or dates keep in flight at the same time.
+	maxConcurrency int
+
+	// timeParser parses the draw times of the draws that methods sort by
+	// time or otherwise need the time of.
+	timeParser DrawTimeParser
 }
 
 type draws struct {
@@ -182,38 +349,123 @@ type PropoDraw struct {
 }
 
 func (s *drawsService) Latest(g Game) (*Draw, *http.Response, error) {
+	return s.latest(context.Background(), g)
+}
+
+// latest is Latest with the request cancelled when ctx is done.
+func (s *drawsService) latest(ctx context.Context, g Game) (*Draw, *http.Response, error) {
 	d := new(draws)
-	u := fmt.Sprintf("%s/%s/last.json", s.Endpoint, g)
-	resp, err := s.client.get(u, d)
+	u := fmt.Sprintf("%s/%s/last.json", s.Endpoint, string(g))
+	resp, err := s.client.getContext(ctx, u, d)
 	if err != nil {
 		return nil, resp, err
 	}
 	return &d.Draw, resp, nil
 }
 
+// LatestWithAge returns the latest draw of game g along with its age, that
+// is how long ago it took place, parsing its DrawTime with the
+// DrawTimeParser of the client. The request is cancelled when ctx is done.
+// It returns a nil draw and a zero age if the draw cannot be fetched or its
+// DrawTime cannot be parsed.
+func (s *drawsService) LatestWithAge(ctx context.Context, g Game) (*Draw, time.Duration, *http.Response, error) {
+	d, resp, err := s.latest(ctx, g)
+	if err != nil {
+		return nil, 0, resp, err
+	}
+	t, err := s.timeParser.Parse(d.DrawTime)
+	if err != nil {
+		return nil, 0, resp, err
+	}
+	return d, now().Sub(t), resp, nil
+}
+
+// LatestIfNewer returns the latest draw of game g only if its number is
+// greater than knownDrawNo. The boolean result reports whether a newer draw
+// was found; when it was not, the draw is nil and the error is nil.
+func (s *drawsService) LatestIfNewer(g Game, knownDrawNo int) (*Draw, bool, *http.Response, error) {
+	d, resp, err := s.Latest(g)
+	if err != nil {
+		return nil, false, resp, err
+	}
+	if d.DrawNo <= knownDrawNo {
+		return nil, false, resp, nil
+	}
+	return d, true, resp, nil
+}
+
 func (s *drawsService) PropoLatest(g PropoGame) (*PropoDraw, *http.Response, error) {
+	return s.propoLatest(context.Background(), g)
+}
+
+// propoLatest is PropoLatest with the request cancelled when ctx is done. Like
+// the other Propo methods, it returns ErrUnknownGame without making a request
+// if g is not one of the Propo games, such as the zero PropoGame.
+func (s *drawsService) propoLatest(ctx context.Context, g PropoGame) (*PropoDraw, *http.Response, error) {
+	if _, err := g.DrawCount(); err != nil {
+		return nil, nil, err
+	}
 	d := new(propoDraws)
-	u := fmt.Sprintf("%s/%s/last.json", s.Endpoint, g)
-	resp, err := s.client.get(u, d)
+	u := fmt.Sprintf("%s/%s/last.json", s.Endpoint, g.Value())
+	resp, err := s.client.getContext(ctx, u, d)
 	if err != nil {
 		return nil, resp, err
 	}
 	return &d.Draw, resp, nil
 }
 
+// PropoLatestIfNewer is like LatestIfNewer for the Propo games.
+func (s *drawsService) PropoLatestIfNewer(g PropoGame, knownDrawNo int) (*PropoDraw, bool, *http.Response, error) {
+	d, resp, err := s.PropoLatest(g)
+	if err != nil {
+		return nil, false, resp, err
+	}
+	if d.DrawNo <= knownDrawNo {
+		return nil, false, resp, nil
+	}
+	return d, true, resp, nil
+}
+
+// ByNumber returns the draw of game g with the given number.
+//
+// Deprecated: Use ByNumberWithContext, which can be cancelled.
 func (s *drawsService) ByNumber(g Game, number int) (*Draw, *http.Response, error) {
+	return s.ByNumberWithContext(context.Background(), g, number)
+}
+
+// ByNumberWithContext returns the draw of game g with the given number. The
+// request is cancelled when ctx is done. Draw numbers start from 1, so it
+// returns an error without making a request if number is not positive.
+func (s *drawsService) ByNumberWithContext(ctx context.Context, g Game, number int) (*Draw, *http.Response, error) {
+	if number <= 0 {
+		return nil, nil, fmt.Errorf("invalid draw number %d", number)
+	}
 	d := new(draws)
-	u := fmt.Sprintf("%s/%s/%d.json", s.Endpoint, g, number)
-	resp, err := s.client.get(u, d)
+	u := fmt.Sprintf("%s/%s/%d.json", s.Endpoint, string(g), number)
+	resp, err := s.client.getContext(ctx, u, d)
 	if err != nil {
 		return nil, resp, err
 	}
 	return &d.Draw, resp, nil
 }
 
+// ByNumberString is like ByNumber but accepts the draw number as a string,
+// for callers that read draw numbers from text such as CSV files. It returns
+// an error without making a request if number is not a valid integer.
+func (s *drawsService) ByNumberString(g Game, number string) (*Draw, *http.Response, error) {
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid draw number %q: %v", number, err)
+	}
+	return s.ByNumberWithContext(context.Background(), g, n)
+}
+
 func (s *drawsService) PropoByNumber(g PropoGame, number int) (*PropoDraw, *http.Response, error) {
+	if _, err := g.DrawCount(); err != nil {
+		return nil, nil, err
+	}
 	d := new(propoDraws)
-	u := fmt.Sprintf("%s/%s/%d.json", s.Endpoint, g, number)
+	u := fmt.Sprintf("%s/%s/%d.json", s.Endpoint, g.Value(), number)
 	resp, err := s.client.get(u, d)
 	if err != nil {
 		return nil, resp, err
@@ -221,21 +473,84 @@ func (s *drawsService) PropoByNumber(g PropoGame, number int) (*PropoDraw, *http
 	return &d.Draw, resp, nil
 }
 
+// now returns the current time. It is a variable so that tests can control
+// which dates are in the future.
+var now = time.Now
+
+// FutureDateError is returned by the methods that fetch the draws of a date,
+// such as ByDate, when the date is after today, without making a request.
+type FutureDateError struct {
+	// Date is midnight UTC of the requested date.
+	Date time.Time
+	// EstimatedDrawTime is meant to hold when the next draw on Date is
+	// expected to take place. The package does not know the draw schedules
+	// of the games, so it is always the zero time.
+	EstimatedDrawTime time.Time
+}
+
+func (e *FutureDateError) Error() string {
+	return fmt.Sprintf("date %s is in the future", e.Date.Format("02-01-2006"))
+}
+
+// drawDate returns the date segment of the drawDate endpoints for the given
+// day, month and year, zero-padded as the service requires, for example
+// "09-01-2018". It returns an error without building the segment if the
+// arguments are not a calendar date, or a *FutureDateError if the date is
+// after today.
+func drawDate(day, month, year int) (string, error) {
+	if month < 1 || month > 12 {
+		return "", fmt.Errorf("invalid month %d", month)
+	}
+	if year < 1 {
+		return "", fmt.Errorf("invalid year %d", year)
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if day < 1 || date.Day() != day {
+		return "", fmt.Errorf("invalid day %d of %s %d", day, time.Month(month), year)
+	}
+	y, m, d := now().Date()
+	if date.After(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)) {
+		return "", &FutureDateError{Date: date}
+	}
+	return fmt.Sprintf("%02d-%02d-%d", day, month, year), nil
+}
+
+// ByDate returns the draws of game g that took place on the given day, month
+// and year. It returns an error without making a request if the arguments are
+// not a calendar date, or a *FutureDateError if the date is in the future.
+//
+// Deprecated: Use ByDateWithContext, which can be cancelled.
 func (s *drawsService) ByDate(g Game, day, month, year int) ([]Draw, *http.Response, error) {
+	return s.ByDateWithContext(context.Background(), g, day, month, year)
+}
+
+// ByDateWithContext is like ByDate but the request is cancelled when ctx is
+// done.
+func (s *drawsService) ByDateWithContext(ctx context.Context, g Game, day, month, year int) ([]Draw, *http.Response, error) {
+	date, err := drawDate(day, month, year)
+	if err != nil {
+		return nil, nil, err
+	}
 	d := new(drawsByDate)
-	date := fmt.Sprintf("%d-%d-%d", day, month, year)
-	u := fmt.Sprintf("%s/%s/drawDate/%s.json", s.Endpoint, g, date)
-	resp, err := s.client.get(u, d)
+	u := fmt.Sprintf("%s/%s/drawDate/%s.json", s.Endpoint, string(g), date)
+	resp, err := s.client.getContext(ctx, u, d)
 	if err != nil {
 		return nil, resp, err
 	}
 	return d.Draws.Draw, resp, nil
 }
 
+// PropoByDate is like ByDate for the Propo games.
 func (s *drawsService) PropoByDate(g PropoGame, day, month, year int) ([]PropoDraw, *http.Response, error) {
+	date, err := drawDate(day, month, year)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := g.DrawCount(); err != nil {
+		return nil, nil, err
+	}
 	d := new(propoDrawsByDate)
-	date := fmt.Sprintf("%d-%d-%d", day, month, year)
-	u := fmt.Sprintf("%s/%s/drawDate/%s.json", s.Endpoint, g, date)
+	u := fmt.Sprintf("%s/%s/drawDate/%s.json", s.Endpoint, g.Value(), date)
 	resp, err := s.client.get(u, d)
 	if err != nil {
 		return nil, resp, err
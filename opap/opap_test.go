@@ -1,12 +1,15 @@
 package opap
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
 	"testing"
+	"time"
 )
 
 var (
@@ -27,7 +30,7 @@ func setup() {
 	server = httptest.NewServer(mux)
 
 	// Opap client configured to use test server
-	client = NewClient(nil)
+	client = NewClient()
 	client.BaseURL, _ = url.Parse(server.URL)
 }
 
@@ -45,11 +48,58 @@ func TestNewClient(t *testing.T) {
 	}
 
 	// test draws default endpoint
-	if got, want := c.Draws.Endpoint, defaultDrawsEndpoint; got != want {
+	if got, want := c.Draws.(*drawsService).Endpoint, defaultDrawsEndpoint; got != want {
 		t.Errorf("NewClient.Draws.Endpoint = %v, want %v", got, want)
 	}
 }
 
+func TestNewClientWithBaseURL(t *testing.T) {
+	rawURL := "http://example.com/"
+	c, err := NewClientWithBaseURL(rawURL, nil)
+	if err != nil {
+		t.Fatalf("NewClientWithBaseURL(%q) returned err = %v", rawURL, err)
+	}
+
+	if got, want := c.BaseURL.String(), rawURL; got != want {
+		t.Errorf("NewClientWithBaseURL.BaseURL = %v, want %v", got, want)
+	}
+
+	if got, want := c.Draws.(*drawsService).Endpoint, defaultDrawsEndpoint; got != want {
+		t.Errorf("NewClientWithBaseURL.Draws.Endpoint = %v, want %v", got, want)
+	}
+}
+
+func TestNewClientWithBaseURL_badURL(t *testing.T) {
+	rawURL := "%foo"
+	if _, err := NewClientWithBaseURL(rawURL, nil); err == nil {
+		t.Errorf("NewClientWithBaseURL(%q) should return parse err", rawURL)
+	}
+}
+
+func TestClient_SetBaseURL(t *testing.T) {
+	c := NewClient(nil)
+
+	rawURL := "http://example.com/"
+	if err := c.SetBaseURL(rawURL); err != nil {
+		t.Fatalf("SetBaseURL(%q) returned err = %v", rawURL, err)
+	}
+	if got, want := c.BaseURL.String(), rawURL; got != want {
+		t.Errorf("SetBaseURL(%q) BaseURL = %v, want %v", rawURL, got, want)
+	}
+}
+
+func TestClient_SetBaseURL_badURL(t *testing.T) {
+	c := NewClient(nil)
+
+	rawURL := "%foo"
+	if err := c.SetBaseURL(rawURL); err == nil {
+		t.Errorf("SetBaseURL(%q) should return parse err", rawURL)
+	}
+	if got, want := c.BaseURL.String(), defaultBaseURL; got != want {
+		t.Errorf("SetBaseURL(%q) BaseURL = %v, want unchanged %v", rawURL, got, want)
+	}
+}
+
 func TestClient_NewRequest(t *testing.T) {
 	c := NewClient(nil)
 
@@ -70,6 +120,19 @@ func testMethod(t *testing.T, r *http.Request, want string) {
 	}
 }
 
+// testErrorResponse checks that err is an *ErrorResponse with the wanted
+// status code.
+func testErrorResponse(t *testing.T, err error, wantStatus int) {
+	t.Helper()
+	errResp, ok := err.(*ErrorResponse)
+	if !ok {
+		t.Fatalf("err type = %T, want *ErrorResponse", err)
+	}
+	if got := errResp.StatusCode; got != wantStatus {
+		t.Errorf("ErrorResponse.StatusCode = %d, want %d", got, wantStatus)
+	}
+}
+
 func TestClient_Do(t *testing.T) {
 	setup()
 	defer teardown()
@@ -116,6 +179,48 @@ func TestClient_Do_notFound(t *testing.T) {
 	if resp == nil {
 		t.Fatal("Expected HTTP 404 error to return response.")
 	}
+
+	testErrorResponse(t, err, 404)
+	if !IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+	if IsServerError(err) {
+		t.Errorf("IsServerError(%v) = true, want false", err)
+	}
+}
+
+func TestErrorResponse_Error(t *testing.T) {
+	u, _ := url.Parse("http://example.com/foo")
+	err := &ErrorResponse{Method: "GET", URL: u, StatusCode: 404, Body: "not found"}
+
+	want := "GET http://example.com/foo: 404 not found"
+	if got := err.Error(); got != want {
+		t.Errorf("ErrorResponse.Error() = %q, want %q", got, want)
+	}
+}
+
+func TestIsNotFound_IsServerError(t *testing.T) {
+	tests := []struct {
+		err           error
+		notFound      bool
+		isServerError bool
+	}{
+		{nil, false, false},
+		{fmt.Errorf("foo"), false, false},
+		{&ErrorResponse{StatusCode: 404}, true, false},
+		{&ErrorResponse{StatusCode: 400}, false, false},
+		{&ErrorResponse{StatusCode: 500}, false, true},
+		{&ErrorResponse{StatusCode: 503}, false, true},
+		{fmt.Errorf("wrapped: %w", &ErrorResponse{StatusCode: 502}), false, true},
+	}
+	for _, tt := range tests {
+		if got := IsNotFound(tt.err); got != tt.notFound {
+			t.Errorf("IsNotFound(%v) = %v, want %v", tt.err, got, tt.notFound)
+		}
+		if got := IsServerError(tt.err); got != tt.isServerError {
+			t.Errorf("IsServerError(%v) = %v, want %v", tt.err, got, tt.isServerError)
+		}
+	}
 }
 
 func TestClient_Do_connectionRefused(t *testing.T) {
@@ -188,6 +293,7 @@ func TestDrawService_Latest_error(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error")
 	}
+	testErrorResponse(t, err, 500)
 	if got, want := resp.StatusCode, 500; got != want {
 		t.Errorf("resp status code = %d, want %d", got, want)
 	}
@@ -213,6 +319,124 @@ func TestDrawService_Latest_emptyObject(t *testing.T) {
 	}
 }
 
+func TestDrawService_LatestWithAge(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draw":{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1,24,15,8]}}`)
+	})
+
+	orig := now
+	defer func() { now = orig }()
+	// 22:00 in Athens is 20:00 UTC in the winter.
+	now = func() time.Time { return time.Date(2017, 12, 24, 21, 30, 0, 0, time.UTC) }
+
+	d, age, _, err := client.Draws.LatestWithAge(context.Background(), Joker)
+	if err != nil {
+		t.Fatal("client.Draws.LatestWithAge returned err:", err)
+	}
+	if got, want := d.DrawNo, 1873; got != want {
+		t.Errorf("client.Draws.LatestWithAge DrawNo = %d, want %d", got, want)
+	}
+	if want := 90 * time.Minute; age != want {
+		t.Errorf("client.Draws.LatestWithAge age = %v, want %v", age, want)
+	}
+}
+
+func TestDrawService_LatestWithAge_drawTimeParser(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draw":{"drawTime":"2017-12-24 20:00","drawNo":1873}}`)
+	})
+
+	orig := now
+	defer func() { now = orig }()
+	now = func() time.Time { return time.Date(2017, 12, 24, 21, 30, 0, 0, time.UTC) }
+
+	c := NewClient(WithBaseURL(client.BaseURL), WithDrawTimeParser(DrawTimeParser{Layout: "2006-01-02 15:04"}))
+	_, age, _, err := c.Draws.LatestWithAge(context.Background(), Joker)
+	if err != nil {
+		t.Fatal("client.Draws.LatestWithAge returned err:", err)
+	}
+	if want := 90 * time.Minute; age != want {
+		t.Errorf("client.Draws.LatestWithAge age = %v, want %v", age, want)
+	}
+}
+
+func TestDrawService_LatestWithAge_badDrawTime(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draw":{"drawTime":"foo","drawNo":1873}}`)
+	})
+
+	d, age, resp, err := client.Draws.LatestWithAge(context.Background(), Joker)
+	if err == nil {
+		t.Fatal("client.Draws.LatestWithAge with malformed draw time expected error")
+	}
+	if d != nil || age != 0 || resp == nil {
+		t.Errorf("client.Draws.LatestWithAge = %v, %v, %v, want nil, 0 and the response", d, age, resp)
+	}
+}
+
+func TestDrawService_LatestIfNewer(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draw":{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1,24,15,8]}}`)
+	})
+
+	var game Game = Joker
+	tests := []struct {
+		known     int
+		wantNewer bool
+	}{
+		{1872, true},
+		{1873, false},
+		{1874, false},
+	}
+	for _, tt := range tests {
+		d, newer, _, err := client.Draws.LatestIfNewer(game, tt.known)
+		if err != nil {
+			t.Fatalf("client.Draws.LatestIfNewer(%q, %d) returned err: %v", game, tt.known, err)
+		}
+		if newer != tt.wantNewer {
+			t.Errorf("client.Draws.LatestIfNewer(%q, %d) newer = %v, want %v", game, tt.known, newer, tt.wantNewer)
+		}
+		if got, want := d != nil, tt.wantNewer; got != want {
+			t.Errorf("client.Draws.LatestIfNewer(%q, %d) returned draw %v, want draw %v", game, tt.known, d, want)
+		}
+	}
+}
+
+func TestDrawService_LatestIfNewer_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	var game Game = Joker
+	_, newer, resp, err := client.Draws.LatestIfNewer(game, 1872)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	testErrorResponse(t, err, 500)
+	if newer {
+		t.Error("client.Draws.LatestIfNewer returned newer = true on error")
+	}
+	if got, want := resp.StatusCode, 500; got != want {
+		t.Errorf("resp status code = %d, want %d", got, want)
+	}
+}
+
 func TestDrawService_ByNumber(t *testing.T) {
 	setup()
 	defer teardown()
@@ -247,11 +471,65 @@ func TestDrawService_ByNumber_error(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error")
 	}
+	testErrorResponse(t, err, 500)
 	if got, want := resp.StatusCode, 500; got != want {
 		t.Errorf("resp status code = %d, want %d", got, want)
 	}
 }
 
+func TestDrawService_ByNumberString(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/1873.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draw":{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1,24,15,8]}}`)
+	})
+
+	var game Game = Joker
+	var number = "1873"
+	d, _, err := client.Draws.ByNumberString(game, number)
+	if err != nil {
+		t.Fatal("client.Draws.ByNumberString returned err:", err)
+	}
+	want := &Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+	if got := d; !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByNumberString(%q, %q) \nhave: %#v\nwant: %#v", game, number, got, want)
+	}
+}
+
+func TestDrawService_ByNumberString_invalidNumber(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %v %v", r.Method, r.URL)
+	})
+
+	var game Game = Joker
+	for _, number := range []string{"", "foo", "18.73"} {
+		if _, _, err := client.Draws.ByNumberString(game, number); err == nil {
+			t.Errorf("client.Draws.ByNumberString(%q, %q) expected error", game, number)
+		}
+	}
+}
+
+func TestDrawService_ByNumber_invalidNumber(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %v", r.URL)
+	})
+
+	var game Game = Joker
+	for _, number := range []int{0, -1} {
+		if _, _, err := client.Draws.ByNumber(game, number); err == nil {
+			t.Errorf("client.Draws.ByNumber(%q, %d) expected error", game, number)
+		}
+	}
+}
+
 func TestDrawService_ByDate(t *testing.T) {
 	setup()
 	defer teardown()
@@ -286,11 +564,133 @@ func TestDrawService_ByDate_error(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error")
 	}
+	testErrorResponse(t, err, 500)
 	if got, want := resp.StatusCode, 500; got != want {
 		t.Errorf("resp status code = %d, want %d", got, want)
 	}
 }
 
+func TestDrawService_ByDate_zeroPadded(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/09-01-2018.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"09-01-2018T22:00:00","drawNo":1878,"results":[3,17,21,38,42,9]}]}}`)
+	})
+
+	var game Game = Joker
+	day, month, year := 9, 1, 2018
+	d, _, err := client.Draws.ByDate(game, day, month, year)
+	if err != nil {
+		t.Fatal("client.Draws.ByDate returned err:", err)
+	}
+	want := []Draw{{DrawTime: "09-01-2018T22:00:00", DrawNo: 1878, Results: []int{3, 17, 21, 38, 42, 9}}}
+	if got := d; !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByDate(%q, %d, %d, %d) \nhave: %#v\nwant: %#v", game, day, month, year, got, want)
+	}
+}
+
+func TestDrawService_ByDate_invalidDate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %v", r.URL)
+	})
+
+	orig := now
+	now = func() time.Time { return time.Date(2018, 1, 9, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = orig }()
+
+	var game Game = Joker
+	tests := []struct {
+		day, month, year int
+	}{
+		{0, 1, 2018},
+		{32, 1, 2018},
+		{29, 2, 2017},
+		{31, 4, 2017},
+		{1, 0, 2018},
+		{1, 13, 2018},
+		{1, 1, 0},
+		{10, 1, 2018},
+		{1, 1, 2019},
+	}
+	for _, tt := range tests {
+		if _, _, err := client.Draws.ByDate(game, tt.day, tt.month, tt.year); err == nil {
+			t.Errorf("client.Draws.ByDate(%q, %d, %d, %d) expected error", game, tt.day, tt.month, tt.year)
+		}
+		if _, _, err := client.Draws.PropoByDate(PropoSat, tt.day, tt.month, tt.year); err == nil {
+			t.Errorf("client.Draws.PropoByDate(%q, %d, %d, %d) expected error", PropoSat, tt.day, tt.month, tt.year)
+		}
+	}
+}
+
+func TestDrawService_ByDate_futureDate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %v", r.URL)
+	})
+
+	orig := now
+	now = func() time.Time { return time.Date(2018, 1, 9, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = orig }()
+
+	var game Game = Joker
+	_, _, err := client.Draws.ByDate(game, 10, 1, 2018)
+	var fe *FutureDateError
+	if !errors.As(err, &fe) {
+		t.Fatalf("client.Draws.ByDate(%q, 10, 1, 2018) err = %v, want a *FutureDateError", game, err)
+	}
+	want := &FutureDateError{Date: time.Date(2018, 1, 10, 0, 0, 0, 0, time.UTC)}
+	if !reflect.DeepEqual(fe, want) {
+		t.Errorf("client.Draws.ByDate(%q, 10, 1, 2018) \nhave: %#v\nwant: %#v", game, fe, want)
+	}
+	if got, want := err.Error(), "date 10-01-2018 is in the future"; got != want {
+		t.Errorf("FutureDateError.Error() = %q, want %q", got, want)
+	}
+}
+
+func TestDrawService_WithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/1873.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/24-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawNo":1873}]}}`)
+	})
+
+	ctx := context.Background()
+	d, _, err := client.Draws.ByNumberWithContext(ctx, Joker, 1873)
+	if err != nil {
+		t.Fatal("client.Draws.ByNumberWithContext returned err:", err)
+	}
+	if got, want := d.DrawNo, 1873; got != want {
+		t.Errorf("client.Draws.ByNumberWithContext DrawNo = %d, want %d", got, want)
+	}
+	dd, _, err := client.Draws.ByDateWithContext(ctx, Joker, 24, 12, 2017)
+	if err != nil {
+		t.Fatal("client.Draws.ByDateWithContext returned err:", err)
+	}
+	if got, want := len(dd), 1; got != want {
+		t.Errorf("client.Draws.ByDateWithContext returned %d draws, want %d", got, want)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, _, err := client.Draws.ByNumberWithContext(cancelled, Joker, 1873); err == nil {
+		t.Error("client.Draws.ByNumberWithContext with cancelled context expected error")
+	}
+	if _, _, err := client.Draws.ByDateWithContext(cancelled, Joker, 24, 12, 2017); err == nil {
+		t.Error("client.Draws.ByDateWithContext with cancelled context expected error")
+	}
+}
+
 func TestDrawService_PropoLatest(t *testing.T) {
 	setup()
 	defer teardown()
@@ -324,11 +724,60 @@ func TestDrawService_PropoLatest_error(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error")
 	}
+	testErrorResponse(t, err, 500)
 	if got, want := resp.StatusCode, 500; got != want {
 		t.Errorf("resp status code = %d, want %d", got, want)
 	}
 }
 
+func TestDrawService_PropoLatestIfNewer(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/last.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draw":{"drawTime":"23-12-2017T16:00:00","drawNo":201751,"results":["2","2","1","X","X","1","X","2","1","1","1","X","2","2"]}}`)
+	})
+
+	var game PropoGame = PropoSat
+
+	d, newer, _, err := client.Draws.PropoLatestIfNewer(game, 201751)
+	if err != nil {
+		t.Fatal("client.Draws.PropoLatestIfNewer returned err:", err)
+	}
+	if d != nil || newer {
+		t.Errorf("client.Draws.PropoLatestIfNewer(%q, 201751) = %v, %v, want nil, false", game, d, newer)
+	}
+
+	d, newer, _, err = client.Draws.PropoLatestIfNewer(game, 201750)
+	if err != nil {
+		t.Fatal("client.Draws.PropoLatestIfNewer returned err:", err)
+	}
+	want := &PropoDraw{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751, Results: []string{"2", "2", "1", "X", "X", "1", "X", "2", "1", "1", "1", "X", "2", "2"}}
+	if !newer || !reflect.DeepEqual(d, want) {
+		t.Errorf("client.Draws.PropoLatestIfNewer(%q, 201750) \nhave: %#v, %v\nwant: %#v, true", game, d, newer, want)
+	}
+}
+
+func TestDrawService_PropoLatestIfNewer_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/last.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	var game PropoGame = PropoSat
+	_, newer, _, err := client.Draws.PropoLatestIfNewer(game, 201750)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	testErrorResponse(t, err, 500)
+	if newer {
+		t.Error("client.Draws.PropoLatestIfNewer returned newer = true on error")
+	}
+}
+
 func TestDrawService_PropoByNumber(t *testing.T) {
 	setup()
 	defer teardown()
@@ -364,6 +813,7 @@ func TestDrawService_PropoByNumber_error(t *testing.T) {
 	if err == nil {
 		t.Error("expected error")
 	}
+	testErrorResponse(t, err, 500)
 	if got, want := resp.StatusCode, 500; got != want {
 		t.Errorf("resp status code = %d, want %d", got, want)
 	}
@@ -390,6 +840,29 @@ func TestDrawService_PropoByDate(t *testing.T) {
 	}
 }
 
+func TestDrawService_PropoByDate_zeroPadded(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/drawDate/06-01-2018.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"06-01-2018T16:00:00","drawNo":201801,"results":["1","X","2","1","1","X","2","2","1","X","1","1","2","X"]}]}}`)
+	})
+
+	var game PropoGame = PropoSat
+	day, month, year := 6, 1, 2018
+	d, _, err := client.Draws.PropoByDate(game, day, month, year)
+	if err != nil {
+		t.Fatal("client.Draws.PropoByDate returned err:", err)
+	}
+	if got, want := len(d), 1; got != want {
+		t.Fatalf("client.Draws.PropoByDate(%q, %d, %d, %d) returned %d draws, want %d", game, day, month, year, got, want)
+	}
+	if got, want := d[0].DrawNo, 201801; got != want {
+		t.Errorf("client.Draws.PropoByDate(%q, %d, %d, %d) DrawNo = %d, want %d", game, day, month, year, got, want)
+	}
+}
+
 func TestDrawService_PropoByDate_error(t *testing.T) {
 	setup()
 	defer teardown()
@@ -403,6 +876,7 @@ func TestDrawService_PropoByDate_error(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error")
 	}
+	testErrorResponse(t, err, 500)
 	if got, want := resp.StatusCode, 500; got != want {
 		t.Errorf("resp status code = %d, want %d", got, want)
 	}
@@ -0,0 +1,348 @@
+// Package opaptest provides fakes of the opap package services for tests of
+// code that uses an opap.Client.
+//
+// For example, to control what the Latest draw of a game is:
+//
+//	c := opap.NewClient()
+//	c.Draws = &opaptest.MockDrawsService{
+//		LatestFunc: func(g opap.Game) (*opap.Draw, *http.Response, error) {
+//			return &opap.Draw{DrawNo: 1873}, nil, nil
+//		},
+//	}
+package opaptest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/nstratos/go-opap/opap"
+)
+
+// MockDrawsService is an opap.DrawsService whose methods call the function
+// field of the same name, for example Latest calls LatestFunc. Calling a
+// method whose function field is nil returns an error.
+type MockDrawsService struct {
+	LatestFunc                 func(g opap.Game) (*opap.Draw, *http.Response, error)
+	LatestWithAgeFunc          func(ctx context.Context, g opap.Game) (*opap.Draw, time.Duration, *http.Response, error)
+	LatestIfNewerFunc          func(g opap.Game, knownDrawNo int) (*opap.Draw, bool, *http.Response, error)
+	ByNumberFunc               func(g opap.Game, number int) (*opap.Draw, *http.Response, error)
+	ByNumberWithContextFunc    func(ctx context.Context, g opap.Game, number int) (*opap.Draw, *http.Response, error)
+	ByNumberStringFunc         func(g opap.Game, number string) (*opap.Draw, *http.Response, error)
+	ByDateFunc                 func(g opap.Game, day, month, year int) ([]opap.Draw, *http.Response, error)
+	ByDateWithContextFunc      func(ctx context.Context, g opap.Game, day, month, year int) ([]opap.Draw, *http.Response, error)
+	ByDateRangeFunc            func(g opap.Game, start, end time.Time, opts ...opap.DrawsOption) ([]opap.Draw, []*http.Response, error)
+	ByDateRangeWithMetricsFunc func(ctx context.Context, g opap.Game, start, end time.Time) ([]opap.Draw, []opap.RequestMetric, error)
+	ByDateRangeByWeekdayFunc   func(ctx context.Context, g opap.Game, start, end time.Time, weekdays ...time.Weekday) ([]opap.Draw, error)
+	ByDateRangeSummaryFunc     func(ctx context.Context, g opap.Game, start, end time.Time) (map[time.Time]int, error)
+	ByDateRangeCSVFunc         func(ctx context.Context, g opap.Game, start, end time.Time, w io.Writer) (int, error)
+	ByNumberRangeFunc          func(ctx context.Context, g opap.Game, from, to int) ([]opap.Draw, error)
+	FetchAllHistoryFunc        func(ctx context.Context, g opap.Game, progress func(fetched, total int)) ([]opap.Draw, error)
+	ByMonthFunc                func(g opap.Game, month, year int) ([]opap.Draw, error)
+	MonthlyStatsFunc           func(ctx context.Context, g opap.Game, year int) ([]opap.MonthlyDrawStats, error)
+	ByDateRangeForAllGamesFunc func(ctx context.Context, start, end time.Time) (map[opap.Game][]opap.Draw, map[opap.Game]error, error)
+	LatestAllFunc              func() (map[opap.Game]*opap.Draw, error)
+	WatchFunc                  func(ctx context.Context, g opap.Game, interval time.Duration) (<-chan *opap.Draw, <-chan error)
+	WatchAllFunc               func(ctx context.Context, interval time.Duration) (<-chan opap.GameDraw, <-chan error)
+
+	PropoLatestFunc          func(g opap.PropoGame) (*opap.PropoDraw, *http.Response, error)
+	PropoLatestIfNewerFunc   func(g opap.PropoGame, knownDrawNo int) (*opap.PropoDraw, bool, *http.Response, error)
+	PropoLatestNFunc         func(g opap.PropoGame, n int) ([]opap.PropoDraw, error)
+	PropoByNumberFunc        func(g opap.PropoGame, number int) (*opap.PropoDraw, *http.Response, error)
+	PropoByWeekFunc          func(g opap.PropoGame, isoYear, isoWeek int) (*opap.PropoDraw, *http.Response, error)
+	PropoByWeekRangeFunc     func(g opap.PropoGame, startYear, startWeek, endYear, endWeek int) ([]opap.PropoDraw, error)
+	PropoByDateFunc          func(g opap.PropoGame, day, month, year int) ([]opap.PropoDraw, *http.Response, error)
+	PropoByDateRangeFunc     func(g opap.PropoGame, start, end time.Time) ([]opap.PropoDraw, []*http.Response, error)
+	PropoByMonthFunc         func(g opap.PropoGame, month, year int) ([]opap.PropoDraw, error)
+	PropoLatestAllFunc       func() (map[opap.PropoGame]*opap.PropoDraw, error)
+	AllLatestByPropoGameFunc func(ctx context.Context) (map[opap.PropoGame]*opap.PropoDraw, map[opap.PropoGame]error, error)
+	WatchPropoFunc           func(ctx context.Context, g opap.PropoGame, interval time.Duration) (<-chan *opap.PropoDraw, <-chan error)
+}
+
+var _ opap.DrawsService = (*MockDrawsService)(nil)
+
+func notSet(method string) error {
+	return fmt.Errorf("opaptest: %s called but %sFunc is not set", method, method)
+}
+
+// notSetChan returns a closed channel that holds the error of notSet.
+func notSetChan(method string) <-chan error {
+	errs := make(chan error, 1)
+	errs <- notSet(method)
+	close(errs)
+	return errs
+}
+
+// Latest calls LatestFunc.
+func (m *MockDrawsService) Latest(g opap.Game) (*opap.Draw, *http.Response, error) {
+	if m.LatestFunc == nil {
+		return nil, nil, notSet("Latest")
+	}
+	return m.LatestFunc(g)
+}
+
+// LatestWithAge calls LatestWithAgeFunc.
+func (m *MockDrawsService) LatestWithAge(ctx context.Context, g opap.Game) (*opap.Draw, time.Duration, *http.Response, error) {
+	if m.LatestWithAgeFunc == nil {
+		return nil, 0, nil, notSet("LatestWithAge")
+	}
+	return m.LatestWithAgeFunc(ctx, g)
+}
+
+// LatestIfNewer calls LatestIfNewerFunc.
+func (m *MockDrawsService) LatestIfNewer(g opap.Game, knownDrawNo int) (*opap.Draw, bool, *http.Response, error) {
+	if m.LatestIfNewerFunc == nil {
+		return nil, false, nil, notSet("LatestIfNewer")
+	}
+	return m.LatestIfNewerFunc(g, knownDrawNo)
+}
+
+// ByNumber calls ByNumberFunc.
+func (m *MockDrawsService) ByNumber(g opap.Game, number int) (*opap.Draw, *http.Response, error) {
+	if m.ByNumberFunc == nil {
+		return nil, nil, notSet("ByNumber")
+	}
+	return m.ByNumberFunc(g, number)
+}
+
+// ByNumberWithContext calls ByNumberWithContextFunc.
+func (m *MockDrawsService) ByNumberWithContext(ctx context.Context, g opap.Game, number int) (*opap.Draw, *http.Response, error) {
+	if m.ByNumberWithContextFunc == nil {
+		return nil, nil, notSet("ByNumberWithContext")
+	}
+	return m.ByNumberWithContextFunc(ctx, g, number)
+}
+
+// ByNumberString calls ByNumberStringFunc.
+func (m *MockDrawsService) ByNumberString(g opap.Game, number string) (*opap.Draw, *http.Response, error) {
+	if m.ByNumberStringFunc == nil {
+		return nil, nil, notSet("ByNumberString")
+	}
+	return m.ByNumberStringFunc(g, number)
+}
+
+// ByDate calls ByDateFunc.
+func (m *MockDrawsService) ByDate(g opap.Game, day, month, year int) ([]opap.Draw, *http.Response, error) {
+	if m.ByDateFunc == nil {
+		return nil, nil, notSet("ByDate")
+	}
+	return m.ByDateFunc(g, day, month, year)
+}
+
+// ByDateWithContext calls ByDateWithContextFunc.
+func (m *MockDrawsService) ByDateWithContext(ctx context.Context, g opap.Game, day, month, year int) ([]opap.Draw, *http.Response, error) {
+	if m.ByDateWithContextFunc == nil {
+		return nil, nil, notSet("ByDateWithContext")
+	}
+	return m.ByDateWithContextFunc(ctx, g, day, month, year)
+}
+
+// ByDateRange calls ByDateRangeFunc.
+func (m *MockDrawsService) ByDateRange(g opap.Game, start, end time.Time, opts ...opap.DrawsOption) ([]opap.Draw, []*http.Response, error) {
+	if m.ByDateRangeFunc == nil {
+		return nil, nil, notSet("ByDateRange")
+	}
+	return m.ByDateRangeFunc(g, start, end, opts...)
+}
+
+// ByDateRangeWithMetrics calls ByDateRangeWithMetricsFunc.
+func (m *MockDrawsService) ByDateRangeWithMetrics(ctx context.Context, g opap.Game, start, end time.Time) ([]opap.Draw, []opap.RequestMetric, error) {
+	if m.ByDateRangeWithMetricsFunc == nil {
+		return nil, nil, notSet("ByDateRangeWithMetrics")
+	}
+	return m.ByDateRangeWithMetricsFunc(ctx, g, start, end)
+}
+
+// ByDateRangeByWeekday calls ByDateRangeByWeekdayFunc.
+func (m *MockDrawsService) ByDateRangeByWeekday(ctx context.Context, g opap.Game, start, end time.Time, weekdays ...time.Weekday) ([]opap.Draw, error) {
+	if m.ByDateRangeByWeekdayFunc == nil {
+		return nil, notSet("ByDateRangeByWeekday")
+	}
+	return m.ByDateRangeByWeekdayFunc(ctx, g, start, end, weekdays...)
+}
+
+// ByDateRangeSummary calls ByDateRangeSummaryFunc.
+func (m *MockDrawsService) ByDateRangeSummary(ctx context.Context, g opap.Game, start, end time.Time) (map[time.Time]int, error) {
+	if m.ByDateRangeSummaryFunc == nil {
+		return nil, notSet("ByDateRangeSummary")
+	}
+	return m.ByDateRangeSummaryFunc(ctx, g, start, end)
+}
+
+// ByDateRangeCSV calls ByDateRangeCSVFunc.
+func (m *MockDrawsService) ByDateRangeCSV(ctx context.Context, g opap.Game, start, end time.Time, w io.Writer) (int, error) {
+	if m.ByDateRangeCSVFunc == nil {
+		return 0, notSet("ByDateRangeCSV")
+	}
+	return m.ByDateRangeCSVFunc(ctx, g, start, end, w)
+}
+
+// ByNumberRange calls ByNumberRangeFunc.
+func (m *MockDrawsService) ByNumberRange(ctx context.Context, g opap.Game, from, to int) ([]opap.Draw, error) {
+	if m.ByNumberRangeFunc == nil {
+		return nil, notSet("ByNumberRange")
+	}
+	return m.ByNumberRangeFunc(ctx, g, from, to)
+}
+
+// FetchAllHistory calls FetchAllHistoryFunc.
+func (m *MockDrawsService) FetchAllHistory(ctx context.Context, g opap.Game, progress func(fetched, total int)) ([]opap.Draw, error) {
+	if m.FetchAllHistoryFunc == nil {
+		return nil, notSet("FetchAllHistory")
+	}
+	return m.FetchAllHistoryFunc(ctx, g, progress)
+}
+
+// ByMonth calls ByMonthFunc.
+func (m *MockDrawsService) ByMonth(g opap.Game, month, year int) ([]opap.Draw, error) {
+	if m.ByMonthFunc == nil {
+		return nil, notSet("ByMonth")
+	}
+	return m.ByMonthFunc(g, month, year)
+}
+
+// MonthlyStats calls MonthlyStatsFunc.
+func (m *MockDrawsService) MonthlyStats(ctx context.Context, g opap.Game, year int) ([]opap.MonthlyDrawStats, error) {
+	if m.MonthlyStatsFunc == nil {
+		return nil, notSet("MonthlyStats")
+	}
+	return m.MonthlyStatsFunc(ctx, g, year)
+}
+
+// ByDateRangeForAllGames calls ByDateRangeForAllGamesFunc.
+func (m *MockDrawsService) ByDateRangeForAllGames(ctx context.Context, start, end time.Time) (map[opap.Game][]opap.Draw, map[opap.Game]error, error) {
+	if m.ByDateRangeForAllGamesFunc == nil {
+		return nil, nil, notSet("ByDateRangeForAllGames")
+	}
+	return m.ByDateRangeForAllGamesFunc(ctx, start, end)
+}
+
+// LatestAll calls LatestAllFunc.
+func (m *MockDrawsService) LatestAll() (map[opap.Game]*opap.Draw, error) {
+	if m.LatestAllFunc == nil {
+		return nil, notSet("LatestAll")
+	}
+	return m.LatestAllFunc()
+}
+
+// Watch calls WatchFunc. If WatchFunc is not set, it returns a closed draws
+// channel and an errors channel that holds the error before it is closed.
+func (m *MockDrawsService) Watch(ctx context.Context, g opap.Game, interval time.Duration) (<-chan *opap.Draw, <-chan error) {
+	if m.WatchFunc == nil {
+		draws := make(chan *opap.Draw)
+		close(draws)
+		return draws, notSetChan("Watch")
+	}
+	return m.WatchFunc(ctx, g, interval)
+}
+
+// WatchAll calls WatchAllFunc.
+func (m *MockDrawsService) WatchAll(ctx context.Context, interval time.Duration) (<-chan opap.GameDraw, <-chan error) {
+	if m.WatchAllFunc == nil {
+		draws := make(chan opap.GameDraw)
+		close(draws)
+		return draws, notSetChan("WatchAll")
+	}
+	return m.WatchAllFunc(ctx, interval)
+}
+
+// PropoLatest calls PropoLatestFunc.
+func (m *MockDrawsService) PropoLatest(g opap.PropoGame) (*opap.PropoDraw, *http.Response, error) {
+	if m.PropoLatestFunc == nil {
+		return nil, nil, notSet("PropoLatest")
+	}
+	return m.PropoLatestFunc(g)
+}
+
+// PropoLatestIfNewer calls PropoLatestIfNewerFunc.
+func (m *MockDrawsService) PropoLatestIfNewer(g opap.PropoGame, knownDrawNo int) (*opap.PropoDraw, bool, *http.Response, error) {
+	if m.PropoLatestIfNewerFunc == nil {
+		return nil, false, nil, notSet("PropoLatestIfNewer")
+	}
+	return m.PropoLatestIfNewerFunc(g, knownDrawNo)
+}
+
+// PropoLatestN calls PropoLatestNFunc.
+func (m *MockDrawsService) PropoLatestN(g opap.PropoGame, n int) ([]opap.PropoDraw, error) {
+	if m.PropoLatestNFunc == nil {
+		return nil, notSet("PropoLatestN")
+	}
+	return m.PropoLatestNFunc(g, n)
+}
+
+// PropoByNumber calls PropoByNumberFunc.
+func (m *MockDrawsService) PropoByNumber(g opap.PropoGame, number int) (*opap.PropoDraw, *http.Response, error) {
+	if m.PropoByNumberFunc == nil {
+		return nil, nil, notSet("PropoByNumber")
+	}
+	return m.PropoByNumberFunc(g, number)
+}
+
+// PropoByWeek calls PropoByWeekFunc.
+func (m *MockDrawsService) PropoByWeek(g opap.PropoGame, isoYear, isoWeek int) (*opap.PropoDraw, *http.Response, error) {
+	if m.PropoByWeekFunc == nil {
+		return nil, nil, notSet("PropoByWeek")
+	}
+	return m.PropoByWeekFunc(g, isoYear, isoWeek)
+}
+
+// PropoByWeekRange calls PropoByWeekRangeFunc.
+func (m *MockDrawsService) PropoByWeekRange(g opap.PropoGame, startYear, startWeek, endYear, endWeek int) ([]opap.PropoDraw, error) {
+	if m.PropoByWeekRangeFunc == nil {
+		return nil, notSet("PropoByWeekRange")
+	}
+	return m.PropoByWeekRangeFunc(g, startYear, startWeek, endYear, endWeek)
+}
+
+// PropoByDate calls PropoByDateFunc.
+func (m *MockDrawsService) PropoByDate(g opap.PropoGame, day, month, year int) ([]opap.PropoDraw, *http.Response, error) {
+	if m.PropoByDateFunc == nil {
+		return nil, nil, notSet("PropoByDate")
+	}
+	return m.PropoByDateFunc(g, day, month, year)
+}
+
+// PropoByDateRange calls PropoByDateRangeFunc.
+func (m *MockDrawsService) PropoByDateRange(g opap.PropoGame, start, end time.Time) ([]opap.PropoDraw, []*http.Response, error) {
+	if m.PropoByDateRangeFunc == nil {
+		return nil, nil, notSet("PropoByDateRange")
+	}
+	return m.PropoByDateRangeFunc(g, start, end)
+}
+
+// PropoByMonth calls PropoByMonthFunc.
+func (m *MockDrawsService) PropoByMonth(g opap.PropoGame, month, year int) ([]opap.PropoDraw, error) {
+	if m.PropoByMonthFunc == nil {
+		return nil, notSet("PropoByMonth")
+	}
+	return m.PropoByMonthFunc(g, month, year)
+}
+
+// PropoLatestAll calls PropoLatestAllFunc.
+func (m *MockDrawsService) PropoLatestAll() (map[opap.PropoGame]*opap.PropoDraw, error) {
+	if m.PropoLatestAllFunc == nil {
+		return nil, notSet("PropoLatestAll")
+	}
+	return m.PropoLatestAllFunc()
+}
+
+// AllLatestByPropoGame calls AllLatestByPropoGameFunc.
+func (m *MockDrawsService) AllLatestByPropoGame(ctx context.Context) (map[opap.PropoGame]*opap.PropoDraw, map[opap.PropoGame]error, error) {
+	if m.AllLatestByPropoGameFunc == nil {
+		return nil, nil, notSet("AllLatestByPropoGame")
+	}
+	return m.AllLatestByPropoGameFunc(ctx)
+}
+
+// WatchPropo calls WatchPropoFunc. If WatchPropoFunc is not set, it behaves
+// like Watch does when WatchFunc is not set.
+func (m *MockDrawsService) WatchPropo(ctx context.Context, g opap.PropoGame, interval time.Duration) (<-chan *opap.PropoDraw, <-chan error) {
+	if m.WatchPropoFunc == nil {
+		draws := make(chan *opap.PropoDraw)
+		close(draws)
+		return draws, notSetChan("WatchPropo")
+	}
+	return m.WatchPropoFunc(ctx, g, interval)
+}
@@ -0,0 +1,44 @@
+package opaptest
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/nstratos/go-opap/opap"
+)
+
+func TestMockDrawsService(t *testing.T) {
+	c := opap.NewClient()
+
+	var gotGame opap.Game
+	want := &opap.Draw{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+	c.Draws = &MockDrawsService{
+		LatestFunc: func(g opap.Game) (*opap.Draw, *http.Response, error) {
+			gotGame = g
+			return want, nil, nil
+		},
+	}
+
+	d, _, err := c.Draws.Latest(opap.Joker)
+	if err != nil {
+		t.Fatal("MockDrawsService.Latest returned err:", err)
+	}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("MockDrawsService.Latest \nhave: %#v\nwant: %#v", d, want)
+	}
+	if gotGame != opap.Joker {
+		t.Errorf("LatestFunc called with game %q, want %q", gotGame, opap.Joker)
+	}
+}
+
+func TestMockDrawsService_funcNotSet(t *testing.T) {
+	m := &MockDrawsService{}
+
+	if _, _, err := m.ByNumber(opap.Joker, 1873); err == nil {
+		t.Error("MockDrawsService.ByNumber without ByNumberFunc expected to return err.")
+	}
+	if _, err := m.PropoLatestN(opap.PropoSat, 2); err == nil {
+		t.Error("MockDrawsService.PropoLatestN without PropoLatestNFunc expected to return err.")
+	}
+}
@@ -0,0 +1,246 @@
+package opap
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ClientOption configures a Client created by NewClient.
+type ClientOption func(*Client)
+
+// WithHTTPClient makes the client send its requests using httpClient. A nil
+// httpClient leaves the client using http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.client = httpClient
+		}
+	}
+}
+
+// WithBaseURL makes the client resolve the URLs of its requests relative to
+// baseURL instead of the default base URL. To use a URL string, see
+// NewClientWithBaseURL or Client.SetBaseURL. A nil baseURL leaves the client
+// using the default base URL.
+func WithBaseURL(baseURL *url.URL) ClientOption {
+	return func(c *Client) {
+		if baseURL != nil {
+			c.BaseURL = baseURL
+		}
+	}
+}
+
+// WithAPIKey makes the client send key as the api_key query parameter of
+// every request, for backends that require an API key. The key is masked in
+// the URLs passed to the Logger of the client. An empty key is ignored.
+func WithAPIKey(key string) ClientOption {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithDrawsEndpoint makes the draws service use endpoint instead of the
+// default DrawsRestServices endpoint.
+func WithDrawsEndpoint(endpoint string) ClientOption {
+	return func(c *Client) {
+		if ds, ok := c.Draws.(*drawsService); ok {
+			ds.Endpoint = endpoint
+		}
+	}
+}
+
+// WithUserAgent makes the client send userAgent as the User-Agent header of
+// every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithMaxConcurrency limits the number of requests that the draws service
+// methods which fetch several draws or dates, such as ByNumberRange, keep in
+// flight at the same time. The default limit is 5. Values less than 1 are
+// ignored.
+func WithMaxConcurrency(n int) ClientOption {
+	return func(c *Client) {
+		if ds, ok := c.Draws.(*drawsService); ok && n >= 1 {
+			ds.maxConcurrency = n
+		}
+	}
+}
+
+// WithRetry makes the client retry requests that fail with a transient error,
+// making up to maxAttempts attempts in total with exponential backoff
+// starting at initialDelay. See RetryTransport for which errors are retried.
+// The transport of the HTTP client is wrapped after all options are applied,
+// so WithRetry can be combined with WithHTTPClient in any order.
+func WithRetry(maxAttempts int, initialDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retry = &RetryTransport{
+			MaxAttempts:  maxAttempts,
+			InitialDelay: initialDelay,
+		}
+	}
+}
+
+// WithTimeout sets the time limit of each request that the client makes,
+// including reading the response body. It overrides the Timeout of an
+// http.Client passed with WithHTTPClient, regardless of the order of the
+// options, without modifying that http.Client. Values less than or equal to
+// zero are ignored.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// WithLogger sets a Logger that is told of every request the client makes
+// that is answered, whatever its status code. Requests that fail before a
+// response arrives, for example because of a connection error, are not
+// logged. A nil Logger is ignored.
+func WithLogger(l Logger) ClientOption {
+	return func(c *Client) {
+		if l != nil {
+			c.logger = l
+		}
+	}
+}
+
+// WithDrawTimeParser sets the DrawTimeParser that the draws service methods
+// which need the times of the draws they fetch parse draw times with: ByMonth,
+// PropoByMonth and MonthlyStats, which sort draws by time, and LatestWithAge.
+// The default is DefaultDrawTimeParser. The parser belongs to the client, so
+// it does not affect the functions and methods of the package that parse draw
+// times without a client, such as Draw.Time, Draw.Before, MostRecentOf,
+// NewDrawSliceStats, DrawTimeToUnix and DrawJSON, which always use
+// DefaultDrawTimeParser. For draws of an endpoint with a different layout,
+// either parse the draw times with p.Parse instead of those, or set
+// DefaultDrawTimeParser to p before making any requests.
+func WithDrawTimeParser(p DrawTimeParser) ClientOption {
+	return func(c *Client) {
+		if ds, ok := c.Draws.(*drawsService); ok {
+			ds.timeParser = p
+		}
+	}
+}
+
+// WithCache makes the client cache the successful responses of its requests
+// in memory, keyed by request URL, and answer repeated requests from the
+// cache until the responses expire after ttl. Responses of the latest draw of
+// a game and of the draws of the current day expire after at most 30 seconds,
+// as a new draw can take place at any time. Responses answered from the
+// cache have the header X-From-Cache set. The cache holds at most maxEntries
+// responses, evicting the least recently used one when full; a maxEntries of
+// zero or less means that it is not bounded. A ttl of zero or less disables
+// the cache.
+func WithCache(maxEntries int, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if ttl > 0 {
+			c.cache = newCacheTransport(maxEntries, ttl)
+		}
+	}
+}
+
+// DrawsOption configures a single call of a DrawsService method that accepts
+// options, such as ByDateRange.
+type DrawsOption func(*drawsOptions)
+
+// drawsOptions holds the settings of the DrawsOptions given to a call.
+type drawsOptions struct {
+	maxResults int
+	order      DrawOrder
+	// excluded holds the days to skip, keyed by dateKey.
+	excluded map[string]struct{}
+	// checkpoint is the DrawNo up to which draws are skipped, and
+	// onCheckpoint is told of the highest DrawNo fetched after each day.
+	checkpoint   int
+	onCheckpoint func(latestDrawNo int)
+}
+
+// newDrawsOptions applies opts to the default settings.
+func newDrawsOptions(opts []DrawsOption) *drawsOptions {
+	o := new(drawsOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithMaxResults makes a call return at most n draws, the first n by DrawNo,
+// and stop making requests as soon as it holds n draws. An n of zero or less
+// means that the draws are not limited.
+func WithMaxResults(n int) DrawsOption {
+	return func(o *drawsOptions) {
+		o.maxResults = n
+	}
+}
+
+// DrawOrder is the order in which a call returns its draws, as set with
+// WithOrder.
+type DrawOrder int
+
+const (
+	// OrderAscending returns the draws from the lowest DrawNo to the
+	// highest. It is the default.
+	OrderAscending DrawOrder = iota
+	// OrderDescending returns the draws from the highest DrawNo to the
+	// lowest, for example to show the latest draws first.
+	OrderDescending
+)
+
+// WithOrder makes a call return its draws in order. Draws with the same DrawNo
+// keep their relative order.
+func WithOrder(order DrawOrder) DrawsOption {
+	return func(o *drawsOptions) {
+		o.order = order
+	}
+}
+
+// WithExcludedDates makes a call skip the days of dates without making a
+// request for them, for example days the games did not run, such as
+// Christmas. Only the calendar date of each of dates, in its own location,
+// is considered.
+func WithExcludedDates(dates ...time.Time) DrawsOption {
+	return func(o *drawsOptions) {
+		if o.excluded == nil {
+			o.excluded = make(map[string]struct{}, len(dates))
+		}
+		for _, d := range dates {
+			o.excluded[dateKey(d)] = struct{}{}
+		}
+	}
+}
+
+// isExcluded reports whether day was excluded with WithExcludedDates.
+func (o *drawsOptions) isExcluded(day time.Time) bool {
+	_, ok := o.excluded[dateKey(day)]
+	return ok
+}
+
+// WithCheckpoint makes a call skip the draws numbered up to lastFetchedDrawNo
+// inclusive, for example to resume a long fetch that was interrupted after
+// the draws up to lastFetchedDrawNo were stored. The days are still fetched,
+// as the service cannot be asked for the draws of a day after a DrawNo.
+func WithCheckpoint(lastFetchedDrawNo int) DrawsOption {
+	return func(o *drawsOptions) {
+		o.checkpoint = lastFetchedDrawNo
+	}
+}
+
+// WithCheckpointCallback makes a call call fn with the highest DrawNo fetched
+// so far after each day that brought new draws, so that the caller can store
+// a checkpoint to resume from with WithCheckpoint.
+func WithCheckpointCallback(fn func(latestDrawNo int)) DrawsOption {
+	return func(o *drawsOptions) {
+		o.onCheckpoint = fn
+	}
+}
+
+// dateKey returns the date of t in the layout of the drawDate endpoints, for
+// example "09-01-2018".
+func dateKey(t time.Time) string {
+	return t.Format("02-01-2006")
+}
@@ -0,0 +1,189 @@
+package opap
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewClient_nilOption(t *testing.T) {
+	c := NewClient(nil)
+
+	if got, want := c.client, http.DefaultClient; got != want {
+		t.Errorf("NewClient(nil) http client = %v, want http.DefaultClient", got)
+	}
+	if got, want := c.BaseURL.String(), defaultBaseURL; got != want {
+		t.Errorf("NewClient(nil).BaseURL = %v, want %v", got, want)
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	httpcl := &http.Client{Timeout: 1 * time.Second}
+	c := NewClient(WithHTTPClient(httpcl))
+	if got, want := c.client, httpcl; got != want {
+		t.Errorf("NewClient(WithHTTPClient(%v)) http client = %v, want %v", httpcl, got, want)
+	}
+
+	c = NewClient(WithHTTPClient(nil))
+	if got, want := c.client, http.DefaultClient; got != want {
+		t.Errorf("NewClient(WithHTTPClient(nil)) http client = %v, want http.DefaultClient", got)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	u, _ := url.Parse("http://example.com/")
+	c := NewClient(WithBaseURL(u))
+	if got, want := c.BaseURL.String(), u.String(); got != want {
+		t.Errorf("NewClient(WithBaseURL(%v)).BaseURL = %v, want %v", u, got, want)
+	}
+
+	c = NewClient(WithBaseURL(nil))
+	if got, want := c.BaseURL.String(), defaultBaseURL; got != want {
+		t.Errorf("NewClient(WithBaseURL(nil)).BaseURL = %v, want %v", got, want)
+	}
+}
+
+func TestWithDrawsEndpoint(t *testing.T) {
+	endpoint := "foo"
+	c := NewClient(WithDrawsEndpoint(endpoint))
+	if got, want := c.Draws.(*drawsService).Endpoint, endpoint; got != want {
+		t.Errorf("NewClient(WithDrawsEndpoint(%q)).Draws.Endpoint = %v, want %v", endpoint, got, want)
+	}
+}
+
+func TestWithAPIKey(t *testing.T) {
+	c := NewClient(WithAPIKey("s3cret"))
+
+	req, _ := c.NewRequest("GET", "foo?page=2", nil)
+	q := req.URL.Query()
+	if got, want := q.Get("api_key"), "s3cret"; got != want {
+		t.Errorf("NewRequest api_key = %q, want %q", got, want)
+	}
+	if got, want := q.Get("page"), "2"; got != want {
+		t.Errorf("NewRequest page = %q, want %q", got, want)
+	}
+
+	req, _ = NewClient(WithAPIKey("")).NewRequest("GET", "foo", nil)
+	if got := req.URL.RawQuery; got != "" {
+		t.Errorf("NewRequest with empty API key query = %q, want none", got)
+	}
+}
+
+func TestWithAPIKey_maskedInLogs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+	})
+
+	l := new(recordingLogger)
+	c := NewClient(WithBaseURL(client.BaseURL), WithLogger(l), WithAPIKey("s3cret"))
+	if _, _, err := c.Draws.Latest(Joker); err != nil {
+		t.Fatal("Latest returned err:", err)
+	}
+	if len(l.entries) != 1 {
+		t.Fatalf("logger called %d times, want 1", len(l.entries))
+	}
+	if got, want := l.entries[0].url, server.URL+"/"+defaultDrawsEndpoint+"/joker/last.json?api_key=xxx"; got != want {
+		t.Errorf("logged URL = %q, want %q", got, want)
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	ua := "go-opap-test"
+	c := NewClient(WithUserAgent(ua))
+
+	req, _ := c.NewRequest("GET", "foo", nil)
+	if got, want := req.Header.Get("User-Agent"), ua; got != want {
+		t.Errorf("NewRequest User-Agent = %q, want %q", got, want)
+	}
+}
+
+func TestNewClient_options(t *testing.T) {
+	httpcl := &http.Client{}
+	u, _ := url.Parse("http://example.com/")
+	c := NewClient(WithHTTPClient(httpcl), WithBaseURL(u), WithDrawsEndpoint("foo"), WithUserAgent("bar"))
+
+	if c.client != httpcl || c.BaseURL != u || c.Draws.(*drawsService).Endpoint != "foo" || c.UserAgent != "bar" {
+		t.Errorf("NewClient with all options = %+v, want all options applied", c)
+	}
+	if got := c.Draws.(*drawsService).client; got != c {
+		t.Errorf("NewClient draws service client = %p, want %p", got, c)
+	}
+}
+
+func TestWithMaxConcurrency(t *testing.T) {
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{1, 1},
+		{20, 20},
+		{0, defaultMaxConcurrency},
+		{-1, defaultMaxConcurrency},
+	}
+	for _, tt := range tests {
+		c := NewClient(WithMaxConcurrency(tt.n))
+		if got := c.Draws.(*drawsService).maxConcurrency; got != tt.want {
+			t.Errorf("NewClient(WithMaxConcurrency(%d)) max concurrency = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	httpcl := &http.Client{Timeout: time.Minute}
+	for _, opts := range [][]ClientOption{
+		{WithHTTPClient(httpcl), WithTimeout(time.Second)},
+		{WithTimeout(time.Second), WithHTTPClient(httpcl)},
+	} {
+		c := NewClient(opts...)
+		if got, want := c.client.Timeout, time.Second; got != want {
+			t.Errorf("NewClient with WithTimeout http client timeout = %v, want %v", got, want)
+		}
+	}
+	if got, want := httpcl.Timeout, time.Minute; got != want {
+		t.Errorf("WithTimeout changed the timeout of the passed http.Client to %v", got)
+	}
+
+	if c := NewClient(WithTimeout(0)); c.client != http.DefaultClient {
+		t.Errorf("NewClient(WithTimeout(0)) http client = %v, want http.DefaultClient", c.client)
+	}
+}
+
+func TestWithTimeout_expires(t *testing.T) {
+	setup()
+	defer teardown()
+
+	done := make(chan struct{})
+	defer close(done)
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+	})
+
+	c := NewClient(WithBaseURL(client.BaseURL), WithTimeout(time.Millisecond))
+	start := time.Now()
+	if _, _, err := c.Draws.Latest(Joker); err == nil {
+		t.Error("Latest with an expired timeout expected error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Latest returned after %v, want it to give up after the 1ms timeout", elapsed)
+	}
+}
+
+func TestWithDrawTimeParser(t *testing.T) {
+	p := DrawTimeParser{Layout: "2006-01-02 15:04"}
+	c := NewClient(WithDrawTimeParser(p))
+
+	if got := c.Draws.(*drawsService).timeParser; got != p {
+		t.Errorf("NewClient(WithDrawTimeParser(%v)) parser = %v", p, got)
+	}
+	if got := NewClient().Draws.(*drawsService).timeParser; got != DefaultDrawTimeParser {
+		t.Errorf("NewClient() parser = %v, want DefaultDrawTimeParser", got)
+	}
+}
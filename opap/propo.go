@@ -0,0 +1,437 @@
+package opap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrInvalidPropoResult is returned when a Propo result is not one of "1",
+// "X" or "2", or its numeric encoding is not one of 1, 0 or 2.
+var ErrInvalidPropoResult = errors.New("invalid propo result")
+
+// PropoResult is the outcome of a Propo match.
+type PropoResult string
+
+// The outcomes of a Propo match.
+const (
+	PropoHome       PropoResult = "1"
+	PropoDrawResult PropoResult = "X"
+	PropoAway       PropoResult = "2"
+)
+
+// ParsePropoResult returns the PropoResult that s holds. It returns
+// ErrInvalidPropoResult if s is not one of "1", "X" or "2".
+func ParsePropoResult(s string) (PropoResult, error) {
+	switch r := PropoResult(s); r {
+	case PropoHome, PropoDrawResult, PropoAway:
+		return r, nil
+	}
+	return "", ErrInvalidPropoResult
+}
+
+// TypedResults returns the results of the draw as PropoResults. It returns
+// ErrInvalidPropoResult if any of the results is not a valid outcome.
+func (d *PropoDraw) TypedResults() ([]PropoResult, error) {
+	results := make([]PropoResult, len(d.Results))
+	for i, s := range d.Results {
+		r, err := ParsePropoResult(s)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = r
+	}
+	return results, nil
+}
+
+// propoColumns are the possible outcomes of a Propo match in the order they
+// appear on a Propo coupon.
+var propoColumns = [3]string{"1", "X", "2"}
+
+// Time parses the DrawTime of the draw like Draw.Time does.
+func (d *PropoDraw) Time() (time.Time, error) {
+	return parseDrawTime(d.DrawTime)
+}
+
+// Contains reports whether r is one of the results of the draw.
+func (d *PropoDraw) Contains(r string) bool {
+	for _, res := range d.Results {
+		if res == r {
+			return true
+		}
+	}
+	return false
+}
+
+// ToMatrix returns the results of the draw as a matrix with one row per match
+// and one column for each of the outcomes "1", "X" and "2". The column of the
+// outcome of each match holds the result while the other columns are left
+// empty.
+func (d *PropoDraw) ToMatrix() [][]string {
+	m := make([][]string, len(d.Results))
+	for i, r := range d.Results {
+		row := make([]string, len(propoColumns))
+		for j, c := range propoColumns {
+			if r == c {
+				row[j] = r
+			}
+		}
+		m[i] = row
+	}
+	return m
+}
+
+// PrintPropoMatrix renders a matrix, as returned by PropoDraw.ToMatrix, to w as
+// a table drawn with Unicode box characters. Each row is prefixed by its match
+// number.
+func PrintPropoMatrix(w io.Writer, m [][]string) error {
+	var buf bytes.Buffer
+	buf.WriteString("┌────┬───┬───┬───┐\n")
+	fmt.Fprintf(&buf, "│    │ %s │ %s │ %s │\n", propoColumns[0], propoColumns[1], propoColumns[2])
+	buf.WriteString("├────┼───┼───┼───┤\n")
+	for i, row := range m {
+		if len(row) != len(propoColumns) {
+			return fmt.Errorf("propo matrix row %d has %d columns, want %d", i+1, len(row), len(propoColumns))
+		}
+		fmt.Fprintf(&buf, "│ %2d │ %1s │ %1s │ %1s │\n", i+1, row[0], row[1], row[2])
+	}
+	buf.WriteString("└────┴───┴───┴───┘\n")
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// ToJSON returns the JSON encoding of the draw.
+func (d *PropoDraw) ToJSON() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// ToJSONString returns the JSON encoding of the draw as a string.
+func (d *PropoDraw) ToJSONString() (string, error) {
+	b, err := d.ToJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// PropoMostRecentOf returns the Propo draw with the highest DrawNo out of
+// draws. When more than one draw has the highest DrawNo, the one with the
+// latest DrawTime is returned. It returns ErrEmptySlice if draws is empty.
+func PropoMostRecentOf(draws []PropoDraw) (*PropoDraw, error) {
+	if len(draws) == 0 {
+		return nil, ErrEmptySlice
+	}
+	latest := &draws[0]
+	for i := 1; i < len(draws); i++ {
+		d := &draws[i]
+		newer, err := isMoreRecent(d.DrawNo, d.DrawTime, latest.DrawNo, latest.DrawTime)
+		if err != nil {
+			return nil, err
+		}
+		if newer {
+			latest = d
+		}
+	}
+	return latest, nil
+}
+
+// PropoResultToInt returns the numeric encoding of Propo result r: 1 for
+// "1", 0 for "X" and 2 for "2". It returns ErrInvalidPropoResult for any
+// other string.
+func PropoResultToInt(r string) (int, error) {
+	switch r {
+	case "1":
+		return 1, nil
+	case "X":
+		return 0, nil
+	case "2":
+		return 2, nil
+	}
+	return 0, ErrInvalidPropoResult
+}
+
+// IntToPropoResult is the inverse of PropoResultToInt. It returns
+// ErrInvalidPropoResult when n is not one of 1, 0 or 2.
+func IntToPropoResult(n int) (string, error) {
+	switch n {
+	case 1:
+		return "1", nil
+	case 0:
+		return "X", nil
+	case 2:
+		return "2", nil
+	}
+	return "", ErrInvalidPropoResult
+}
+
+// ResultsAsInts returns the results of the draw in the numeric encoding of
+// PropoResultToInt.
+func (d *PropoDraw) ResultsAsInts() ([]int, error) {
+	results := make([]int, len(d.Results))
+	for i, r := range d.Results {
+		n, err := PropoResultToInt(r)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = n
+	}
+	return results, nil
+}
+
+// isoWeeksInYear returns the number of ISO 8601 weeks of year, which is 52 or
+// 53. December 28th always falls in the last week of its year.
+func isoWeeksInYear(year int) int {
+	_, w := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return w
+}
+
+// PropoDrawNoBefore returns the number of the Propo draw that took place n
+// weeks before the draw numbered current. Propo draw numbers encode the year
+// and the ISO week of the draw as YYYYWW, for example 201751, so going back
+// past the first week of a year continues from the last week, 52 or 53, of
+// the previous year. A negative n moves forward instead.
+func PropoDrawNoBefore(current, n int) int {
+	year, week := current/100, current%100-n
+	for week < 1 {
+		year--
+		week += isoWeeksInYear(year)
+	}
+	for week > isoWeeksInYear(year) {
+		week -= isoWeeksInYear(year)
+		year++
+	}
+	return year*100 + week
+}
+
+// maxMissingPropoWeeks is how many weeks without a coupon PropoLatestN skips
+// before giving up, which covers the summer break of the football season.
+const maxMissingPropoWeeks = 26
+
+// PropoLatestN returns the n most recent draws of Propo game g, oldest
+// first. It fetches the latest draw and then walks back week by week,
+// fetching each draw by number. Not every week has a Propo coupon, so weeks
+// without a draw are skipped, up to 26 of them in total, after
+// which an error is returned.
+func (s *drawsService) PropoLatestN(g PropoGame, n int) ([]PropoDraw, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid number of draws %d", n)
+	}
+	latest, _, err := s.PropoLatest(g)
+	if err != nil {
+		return nil, err
+	}
+	draws := make([]PropoDraw, n)
+	draws[n-1] = *latest
+	found, missing := 1, 0
+	for weeks := 1; found < n; weeks++ {
+		d, _, err := s.PropoByNumber(g, PropoDrawNoBefore(latest.DrawNo, weeks))
+		if IsNotFound(err) {
+			if missing++; missing > maxMissingPropoWeeks {
+				return nil, fmt.Errorf("found %d of %d draws of %v after skipping %d weeks without a draw", found, n, g, maxMissingPropoWeeks)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found++
+		draws[n-found] = *d
+	}
+	return draws, nil
+}
+
+// PropoByWeek returns the draw of Propo game g that took place in ISO week
+// isoWeek of isoYear, using the YYYYWW encoding of Propo draw numbers. It
+// returns an error without making a request if isoWeek is not a week of
+// isoYear.
+func (s *drawsService) PropoByWeek(g PropoGame, isoYear, isoWeek int) (*PropoDraw, *http.Response, error) {
+	if isoWeek < 1 || isoWeek > isoWeeksInYear(isoYear) {
+		return nil, nil, fmt.Errorf("invalid ISO week %d of %d", isoWeek, isoYear)
+	}
+	return s.PropoByNumber(g, isoYear*100+isoWeek)
+}
+
+// Copy returns a deep copy of the draw with its own Results slice. Like
+// Draw.Copy, it costs an allocation per call and should be used only when the
+// results of either draw are going to be modified.
+func (d *PropoDraw) Copy() PropoDraw {
+	c := *d
+	if d.Results != nil {
+		c.Results = make([]string, len(d.Results))
+		copy(c.Results, d.Results)
+	}
+	return c
+}
+
+// PropoDrawFromJSON decodes a Propo draw from data in the format that the
+// PropoLatest and PropoByNumber endpoints of the OPAP REST service respond
+// with, that is {"draw":{...}}.
+func PropoDrawFromJSON(data []byte) (*PropoDraw, error) {
+	d := new(propoDraws)
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, err
+	}
+	return &d.Draw, nil
+}
+
+// NewPropoDrawFromMap is like NewDrawFromMap for Propo draws, whose results
+// must be strings.
+func NewPropoDrawFromMap(m map[string]interface{}) (*PropoDraw, error) {
+	drawTime, drawNo, raw, err := drawMapFields(m)
+	if err != nil {
+		return nil, err
+	}
+	d := &PropoDraw{DrawTime: drawTime, DrawNo: drawNo, Results: make([]string, len(raw))}
+	for i, v := range raw {
+		r, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("draw map result %d is %T, want string", i, v)
+		}
+		d.Results[i] = r
+	}
+	return d, nil
+}
+
+// StringPair holds the results of two Propo draws at the same position.
+type StringPair struct {
+	A, B string
+}
+
+// ZipPropoDrawResults pairs the results of Propo draws a and b by position.
+// It returns an error if the draws do not have the same number of results.
+func ZipPropoDrawResults(a, b PropoDraw) ([]StringPair, error) {
+	if len(a.Results) != len(b.Results) {
+		return nil, fmt.Errorf("draws have %d and %d results", len(a.Results), len(b.Results))
+	}
+	pairs := make([]StringPair, len(a.Results))
+	for i := range a.Results {
+		pairs[i] = StringPair{A: a.Results[i], B: b.Results[i]}
+	}
+	return pairs, nil
+}
+
+// SortedSet returns the distinct results of the draw in ascending order,
+// leaving Results untouched. Results are compared case-sensitively, so "x"
+// and "X" are distinct.
+func (d *PropoDraw) SortedSet() []string {
+	seen := make(map[string]struct{}, len(d.Results))
+	set := make([]string, 0, len(d.Results))
+	for _, r := range d.Results {
+		if _, ok := seen[r]; !ok {
+			seen[r] = struct{}{}
+			set = append(set, r)
+		}
+	}
+	sort.Strings(set)
+	return set
+}
+
+// PropoByWeekRange returns the draws of Propo game g from ISO week startWeek
+// of startYear to ISO week endWeek of endYear inclusive, sorted by DrawNo.
+// The weeks are fetched concurrently. Weeks that the service reports as not
+// found, such as weeks without a Propo coupon, are skipped. Any other error
+// stops the fetching and is returned. It returns an error without making a
+// request if a week is not valid or the start week is after the end week.
+func (s *drawsService) PropoByWeekRange(g PropoGame, startYear, startWeek, endYear, endWeek int) ([]PropoDraw, error) {
+	for _, yw := range [][2]int{{startYear, startWeek}, {endYear, endWeek}} {
+		if yw[1] < 1 || yw[1] > isoWeeksInYear(yw[0]) {
+			return nil, fmt.Errorf("invalid ISO week %d of %d", yw[1], yw[0])
+		}
+	}
+	if startYear*100+startWeek > endYear*100+endWeek {
+		return nil, fmt.Errorf("start week %d of %d is after end week %d of %d", startWeek, startYear, endWeek, endYear)
+	}
+
+	var numbers []int
+	last := endYear*100 + endWeek
+	for no := startYear*100 + startWeek; no <= last; no = PropoDrawNoBefore(no, -1) {
+		numbers = append(numbers, no)
+	}
+
+	var (
+		mu    sync.Mutex
+		draws []PropoDraw
+	)
+	err := concurrently(len(numbers), s.maxConcurrency, func(i int) error {
+		d, _, err := s.PropoByNumber(g, numbers[i])
+		if IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		draws = append(draws, *d)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(draws, func(i, j int) bool { return draws[i].DrawNo < draws[j].DrawNo })
+	return draws, nil
+}
+
+// PropoDrawSliceStats is like DrawSliceStats for Propo draws.
+type PropoDrawSliceStats DrawSliceStats
+
+// NewPropoDrawSliceStats is like NewDrawSliceStats for Propo draws.
+func NewPropoDrawSliceStats(draws []PropoDraw) (PropoDrawSliceStats, error) {
+	var st DrawSliceStats
+	if len(draws) == 0 {
+		return PropoDrawSliceStats{}, ErrEmptySlice
+	}
+	for i := range draws {
+		t, err := draws[i].Time()
+		if err != nil {
+			return PropoDrawSliceStats{}, err
+		}
+		st.add(draws[i].DrawNo, t)
+	}
+	return PropoDrawSliceStats(st), nil
+}
+
+// Before is like Draw.Before for Propo draws.
+func (d *PropoDraw) Before(other PropoDraw) (bool, error) {
+	t, u, err := parseDrawTimes(d.DrawTime, other.DrawTime)
+	if err != nil {
+		return false, err
+	}
+	return t.Before(u), nil
+}
+
+// After is like Draw.After for Propo draws.
+func (d *PropoDraw) After(other PropoDraw) (bool, error) {
+	t, u, err := parseDrawTimes(d.DrawTime, other.DrawTime)
+	if err != nil {
+		return false, err
+	}
+	return t.After(u), nil
+}
+
+// SortPropoDrawsByTime is like SortDrawsByTime for Propo draws.
+func SortPropoDrawsByTime(draws []PropoDraw) error {
+	return sortPropoDrawsByTime(draws, parseDrawTime)
+}
+
+// sortPropoDrawsByTime is SortPropoDrawsByTime with draw times parsed by
+// parse.
+func sortPropoDrawsByTime(draws []PropoDraw, parse func(string) (time.Time, error)) error {
+	times := make([]time.Time, len(draws))
+	for i := range draws {
+		t, err := parse(draws[i].DrawTime)
+		if err != nil {
+			return err
+		}
+		times[i] = t
+	}
+	sort.Stable(byTime{len(draws), times, func(i, j int) { draws[i], draws[j] = draws[j], draws[i] }})
+	return nil
+}
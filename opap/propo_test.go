@@ -0,0 +1,633 @@
+package opap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPropoDraw_Time(t *testing.T) {
+	for _, tt := range drawTimeTests {
+		d := &PropoDraw{DrawTime: tt.in}
+		got, err := d.Time()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("PropoDraw{DrawTime: %q}.Time() err = %v, want err %v", tt.in, err, tt.wantErr)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("PropoDraw{DrawTime: %q}.Time() = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPropoDraw_Contains(t *testing.T) {
+	d := &PropoDraw{Results: []string{"2", "2", "1"}}
+
+	tests := []struct {
+		r    string
+		want bool
+	}{
+		{"1", true},
+		{"2", true},
+		{"X", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := d.Contains(tt.r); got != tt.want {
+			t.Errorf("PropoDraw{Results: %q}.Contains(%q) = %v, want %v", d.Results, tt.r, got, tt.want)
+		}
+	}
+	if (&PropoDraw{}).Contains("1") {
+		t.Error(`PropoDraw{}.Contains("1") = true, want false`)
+	}
+}
+
+func TestPropoDraw_ToMatrix(t *testing.T) {
+	d := &PropoDraw{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751, Results: []string{"2", "2", "1", "X", "X", "1", "X", "2", "1", "1", "1", "X", "2", "2"}}
+
+	want := [][]string{
+		{"", "", "2"},
+		{"", "", "2"},
+		{"1", "", ""},
+		{"", "X", ""},
+		{"", "X", ""},
+		{"1", "", ""},
+		{"", "X", ""},
+		{"", "", "2"},
+		{"1", "", ""},
+		{"1", "", ""},
+		{"1", "", ""},
+		{"", "X", ""},
+		{"", "", "2"},
+		{"", "", "2"},
+	}
+	if got := d.ToMatrix(); !reflect.DeepEqual(got, want) {
+		t.Errorf("PropoDraw.ToMatrix() \nhave: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestPropoDraw_ToMatrix_unknownResult(t *testing.T) {
+	d := &PropoDraw{Results: []string{"1", "-"}}
+
+	want := [][]string{{"1", "", ""}, {"", "", ""}}
+	if got := d.ToMatrix(); !reflect.DeepEqual(got, want) {
+		t.Errorf("PropoDraw.ToMatrix() \nhave: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestPrintPropoMatrix(t *testing.T) {
+	d := &PropoDraw{Results: []string{"2", "1", "X"}}
+
+	var buf bytes.Buffer
+	if err := PrintPropoMatrix(&buf, d.ToMatrix()); err != nil {
+		t.Fatal("PrintPropoMatrix returned err:", err)
+	}
+	want := "" +
+		"┌────┬───┬───┬───┐\n" +
+		"│    │ 1 │ X │ 2 │\n" +
+		"├────┼───┼───┼───┤\n" +
+		"│  1 │   │   │ 2 │\n" +
+		"│  2 │ 1 │   │   │\n" +
+		"│  3 │   │ X │   │\n" +
+		"└────┴───┴───┴───┘\n"
+	if got := buf.String(); got != want {
+		t.Errorf("PrintPropoMatrix output \nhave:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPrintPropoMatrix_badRow(t *testing.T) {
+	var buf bytes.Buffer
+	if err := PrintPropoMatrix(&buf, [][]string{{"1", ""}}); err == nil {
+		t.Error("PrintPropoMatrix with short row expected to return err.")
+	}
+}
+
+func TestPropoDraw_ToJSON(t *testing.T) {
+	d := &PropoDraw{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751, Results: []string{"2", "2", "1", "X"}}
+
+	b, err := d.ToJSON()
+	if err != nil {
+		t.Fatal("PropoDraw.ToJSON returned err:", err)
+	}
+	s, err := d.ToJSONString()
+	if err != nil {
+		t.Fatal("PropoDraw.ToJSONString returned err:", err)
+	}
+	want := `{"drawTime":"23-12-2017T16:00:00","drawNo":201751,"results":["2","2","1","X"]}`
+	if got := string(b); got != want {
+		t.Errorf("PropoDraw.ToJSON() \nhave: %s\nwant: %s", got, want)
+	}
+	if got := s; got != want {
+		t.Errorf("PropoDraw.ToJSONString() \nhave: %s\nwant: %s", got, want)
+	}
+
+	got := new(PropoDraw)
+	if err := json.Unmarshal([]byte(s), got); err != nil {
+		t.Fatal("decoding PropoDraw.ToJSONString output returned err:", err)
+	}
+	if !reflect.DeepEqual(got, d) {
+		t.Errorf("PropoDraw JSON round trip \nhave: %#v\nwant: %#v", got, d)
+	}
+}
+
+func TestPropoMostRecentOf(t *testing.T) {
+	draws := []PropoDraw{
+		{DrawNo: 201750, DrawTime: "16-12-2017T16:00:00"},
+		{DrawNo: 201751, DrawTime: "23-12-2017T15:00:00"},
+		{DrawNo: 201751, DrawTime: "23-12-2017T16:00:00"},
+	}
+	got, err := PropoMostRecentOf(draws)
+	if err != nil {
+		t.Fatal("PropoMostRecentOf returned err:", err)
+	}
+	want := &PropoDraw{DrawNo: 201751, DrawTime: "23-12-2017T16:00:00"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PropoMostRecentOf \nhave: %#v\nwant: %#v", got, want)
+	}
+
+	if _, err := PropoMostRecentOf(nil); err != ErrEmptySlice {
+		t.Errorf("PropoMostRecentOf(nil) err = %v, want %v", err, ErrEmptySlice)
+	}
+}
+
+func TestPropoResultToInt(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr error
+	}{
+		{"1", 1, nil},
+		{"X", 0, nil},
+		{"2", 2, nil},
+		{"x", 0, ErrInvalidPropoResult},
+		{"0", 0, ErrInvalidPropoResult},
+		{"", 0, ErrInvalidPropoResult},
+		{" 1", 0, ErrInvalidPropoResult},
+	}
+	for _, tt := range tests {
+		got, err := PropoResultToInt(tt.in)
+		if err != tt.wantErr {
+			t.Errorf("PropoResultToInt(%q) err = %v, want %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("PropoResultToInt(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIntToPropoResult(t *testing.T) {
+	tests := []struct {
+		in      int
+		want    string
+		wantErr error
+	}{
+		{1, "1", nil},
+		{0, "X", nil},
+		{2, "2", nil},
+		{-1, "", ErrInvalidPropoResult},
+		{3, "", ErrInvalidPropoResult},
+	}
+	for _, tt := range tests {
+		got, err := IntToPropoResult(tt.in)
+		if err != tt.wantErr {
+			t.Errorf("IntToPropoResult(%d) err = %v, want %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("IntToPropoResult(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPropoDraw_ResultsAsInts(t *testing.T) {
+	d := &PropoDraw{Results: []string{"2", "1", "X"}}
+	got, err := d.ResultsAsInts()
+	if err != nil {
+		t.Fatal("PropoDraw.ResultsAsInts returned err:", err)
+	}
+	if want := []int{2, 1, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PropoDraw.ResultsAsInts() = %v, want %v", got, want)
+	}
+
+	d = &PropoDraw{Results: []string{"2", "-"}}
+	if _, err := d.ResultsAsInts(); err != ErrInvalidPropoResult {
+		t.Errorf("PropoDraw.ResultsAsInts() err = %v, want %v", err, ErrInvalidPropoResult)
+	}
+}
+
+func TestPropoDrawNoBefore(t *testing.T) {
+	tests := []struct {
+		current, n int
+		want       int
+	}{
+		{201751, 0, 201751},
+		{201751, 1, 201750},
+		{201751, 50, 201701},
+		{201801, 1, 201752},
+		{201601, 1, 201553}, // 2015 has 53 ISO weeks
+		{201601, 54, 201452},
+		{201752, -1, 201801},
+		{201553, -1, 201601},
+	}
+	for _, tt := range tests {
+		if got := PropoDrawNoBefore(tt.current, tt.n); got != tt.want {
+			t.Errorf("PropoDrawNoBefore(%d, %d) = %d, want %d", tt.current, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestDrawService_PropoLatestN(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/last.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draw":{"drawTime":"06-01-2018T16:00:00","drawNo":201801,"results":["1","X"]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/201752.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draw":{"drawTime":"30-12-2017T16:00:00","drawNo":201752,"results":["2","2"]}}`)
+	})
+
+	var game PropoGame = PropoSat
+	got, err := client.Draws.PropoLatestN(game, 2)
+	if err != nil {
+		t.Fatal("client.Draws.PropoLatestN returned err:", err)
+	}
+	want := []PropoDraw{
+		{DrawTime: "30-12-2017T16:00:00", DrawNo: 201752, Results: []string{"2", "2"}},
+		{DrawTime: "06-01-2018T16:00:00", DrawNo: 201801, Results: []string{"1", "X"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.PropoLatestN(%q, 2) \nhave: %#v\nwant: %#v", game, got, want)
+	}
+}
+
+func TestDrawService_PropoLatestN_skipsMissingWeeks(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/last.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draw":{"drawTime":"06-01-2018T16:00:00","drawNo":201801,"results":["1","X"]}}`)
+	})
+	// There is no coupon in week 52 of 2017, so it is answered with 404.
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/201752.json", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/201751.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draw":{"drawTime":"23-12-2017T16:00:00","drawNo":201751,"results":["2","2"]}}`)
+	})
+
+	var game PropoGame = PropoSat
+	got, err := client.Draws.PropoLatestN(game, 2)
+	if err != nil {
+		t.Fatal("client.Draws.PropoLatestN returned err:", err)
+	}
+	want := []PropoDraw{
+		{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751, Results: []string{"2", "2"}},
+		{DrawTime: "06-01-2018T16:00:00", DrawNo: 201801, Results: []string{"1", "X"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.PropoLatestN(%q, 2) \nhave: %#v\nwant: %#v", game, got, want)
+	}
+}
+
+func TestDrawService_PropoLatestN_tooManyMissingWeeks(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var requests int
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/"+defaultDrawsEndpoint+"/proposat/last.json" {
+			fmt.Fprint(w, `{"draw":{"drawTime":"06-01-2018T16:00:00","drawNo":201801,"results":["1","X"]}}`)
+			return
+		}
+		requests++
+		http.NotFound(w, r)
+	})
+
+	var game PropoGame = PropoSat
+	if _, err := client.Draws.PropoLatestN(game, 2); err == nil {
+		t.Fatal("client.Draws.PropoLatestN without earlier draws expected error")
+	}
+	if got, want := requests, maxMissingPropoWeeks+1; got != want {
+		t.Errorf("client.Draws.PropoLatestN made %d requests for earlier draws, want %d", got, want)
+	}
+}
+
+func TestDrawService_PropoLatestN_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/last.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draw":{"drawTime":"06-01-2018T16:00:00","drawNo":201801,"results":["1","X"]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/201752.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	var game PropoGame = PropoSat
+	_, err := client.Draws.PropoLatestN(game, 2)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	testErrorResponse(t, err, 500)
+	if _, err := client.Draws.PropoLatestN(game, 0); err == nil {
+		t.Fatal("expected error for n = 0")
+	}
+}
+
+func TestDrawService_PropoByWeek(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/201751.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draw":{"drawTime":"23-12-2017T16:00:00","drawNo":201751,"results":["2","2","1","X","X","1","X","2","1","1","1","X","2","2"]}}`)
+	})
+
+	var game PropoGame = PropoSat
+	year, week := 2017, 51
+	d, _, err := client.Draws.PropoByWeek(game, year, week)
+	if err != nil {
+		t.Fatal("client.Draws.PropoByWeek returned err:", err)
+	}
+	want := &PropoDraw{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751, Results: []string{"2", "2", "1", "X", "X", "1", "X", "2", "1", "1", "1", "X", "2", "2"}}
+	if got := d; !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.PropoByWeek(%q, %d, %d) \nhave: %#v\nwant: %#v", game, year, week, got, want)
+	}
+}
+
+func TestDrawService_PropoByWeek_invalidWeek(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %v %v", r.Method, r.URL)
+	})
+
+	var game PropoGame = PropoSat
+	tests := []struct{ year, week int }{
+		{2017, 0},
+		{2017, 53}, // 2017 has 52 ISO weeks
+		{2015, 54},
+	}
+	for _, tt := range tests {
+		if _, _, err := client.Draws.PropoByWeek(game, tt.year, tt.week); err == nil {
+			t.Errorf("client.Draws.PropoByWeek(%q, %d, %d) expected error", game, tt.year, tt.week)
+		}
+	}
+}
+
+func TestPropoDraw_Copy(t *testing.T) {
+	d := &PropoDraw{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751, Results: []string{"2", "2", "1"}}
+
+	c := d.Copy()
+	if !reflect.DeepEqual(&c, d) {
+		t.Fatalf("PropoDraw.Copy() \nhave: %#v\nwant: %#v", c, d)
+	}
+	c.Results[0] = "X"
+	if got, want := d.Results[0], "2"; got != want {
+		t.Errorf("modifying copy changed original Results[0] to %q, want %q", got, want)
+	}
+}
+
+func TestPropoDrawFromJSON(t *testing.T) {
+	data := []byte(`{"draw":{"drawTime":"23-12-2017T16:00:00","drawNo":201751,"results":["2","2","1"]}}`)
+
+	got, err := PropoDrawFromJSON(data)
+	if err != nil {
+		t.Fatal("PropoDrawFromJSON returned err:", err)
+	}
+	want := &PropoDraw{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751, Results: []string{"2", "2", "1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PropoDrawFromJSON(%s) \nhave: %#v\nwant: %#v", data, got, want)
+	}
+
+	if _, err := PropoDrawFromJSON([]byte(`<html>something broke</html>`)); err == nil {
+		t.Error("PropoDrawFromJSON with HTML expected to return err.")
+	}
+}
+
+func TestZipPropoDrawResults(t *testing.T) {
+	a := PropoDraw{Results: []string{"1", "X"}}
+	b := PropoDraw{Results: []string{"2", "X"}}
+
+	got, err := ZipPropoDrawResults(a, b)
+	if err != nil {
+		t.Fatal("ZipPropoDrawResults returned err:", err)
+	}
+	want := []StringPair{{"1", "2"}, {"X", "X"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ZipPropoDrawResults(%v, %v) = %v, want %v", a, b, got, want)
+	}
+
+	if _, err := ZipPropoDrawResults(a, PropoDraw{}); err == nil {
+		t.Error("ZipPropoDrawResults with different lengths expected to return err.")
+	}
+}
+
+func TestPropoDraw_SortedSet(t *testing.T) {
+	d := &PropoDraw{Results: []string{"X", "2", "x", "1", "2", "X"}}
+
+	want := []string{"1", "2", "X", "x"}
+	if got := d.SortedSet(); !reflect.DeepEqual(got, want) {
+		t.Errorf("PropoDraw.SortedSet() = %q, want %q", got, want)
+	}
+}
+
+func TestDrawService_PropoByWeekRange(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, no := range []string{"201751", "201752", "201802"} {
+		no := no
+		mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/"+no+".json", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			fmt.Fprintf(w, `{"draw":{"drawNo":%s,"results":["1"]}}`, no)
+		})
+	}
+	// 201801 is not handled by the mux and is reported as not found.
+
+	var game PropoGame = PropoSat
+	got, err := client.Draws.PropoByWeekRange(game, 2017, 51, 2018, 2)
+	if err != nil {
+		t.Fatal("client.Draws.PropoByWeekRange returned err:", err)
+	}
+	want := []PropoDraw{
+		{DrawNo: 201751, Results: []string{"1"}},
+		{DrawNo: 201752, Results: []string{"1"}},
+		{DrawNo: 201802, Results: []string{"1"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.PropoByWeekRange(%q, 2017, 51, 2018, 2) \nhave: %#v\nwant: %#v", game, got, want)
+	}
+}
+
+func TestDrawService_PropoByWeekRange_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/201752.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	var game PropoGame = PropoSat
+	_, err := client.Draws.PropoByWeekRange(game, 2017, 51, 2018, 1)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	testErrorResponse(t, err, 500)
+
+	if _, err := client.Draws.PropoByWeekRange(game, 2017, 53, 2018, 1); err == nil {
+		t.Error("client.Draws.PropoByWeekRange with invalid week expected to return err.")
+	}
+	if _, err := client.Draws.PropoByWeekRange(game, 2018, 1, 2017, 51); err == nil {
+		t.Error("client.Draws.PropoByWeekRange with start after end expected to return err.")
+	}
+}
+
+func TestNewPropoDrawSliceStats(t *testing.T) {
+	draws := []PropoDraw{
+		{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751},
+		{DrawTime: "16-12-2017T16:00:00", DrawNo: 201750},
+	}
+	got, err := NewPropoDrawSliceStats(draws)
+	if err != nil {
+		t.Fatal("NewPropoDrawSliceStats returned err:", err)
+	}
+	want := PropoDrawSliceStats{
+		Count:            2,
+		EarliestDrawNo:   201750,
+		LatestDrawNo:     201751,
+		EarliestDrawTime: time.Date(2017, 12, 16, 16, 0, 0, 0, athens),
+		LatestDrawTime:   time.Date(2017, 12, 23, 16, 0, 0, 0, athens),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewPropoDrawSliceStats \nhave: %#v\nwant: %#v", got, want)
+	}
+
+	if _, err := NewPropoDrawSliceStats(nil); err != ErrEmptySlice {
+		t.Errorf("NewPropoDrawSliceStats(nil) err = %v, want %v", err, ErrEmptySlice)
+	}
+}
+
+func TestParsePropoResult(t *testing.T) {
+	tests := []struct {
+		in   string
+		want PropoResult
+	}{
+		{"1", PropoHome},
+		{"X", PropoDrawResult},
+		{"2", PropoAway},
+	}
+	for _, tt := range tests {
+		got, err := ParsePropoResult(tt.in)
+		if err != nil {
+			t.Errorf("ParsePropoResult(%q) returned err: %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("ParsePropoResult(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	for _, in := range []string{"x", "0", ""} {
+		if _, err := ParsePropoResult(in); err != ErrInvalidPropoResult {
+			t.Errorf("ParsePropoResult(%q) err = %v, want %v", in, err, ErrInvalidPropoResult)
+		}
+	}
+}
+
+func TestPropoDraw_TypedResults(t *testing.T) {
+	d := &PropoDraw{Results: []string{"2", "X", "1"}}
+	want := []PropoResult{PropoAway, PropoDrawResult, PropoHome}
+	got, err := d.TypedResults()
+	if err != nil {
+		t.Fatal("PropoDraw.TypedResults returned err:", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PropoDraw.TypedResults() = %q, want %q", got, want)
+	}
+
+	d = &PropoDraw{Results: []string{"2", ""}}
+	if _, err := d.TypedResults(); err != ErrInvalidPropoResult {
+		t.Errorf("PropoDraw.TypedResults() err = %v, want %v", err, ErrInvalidPropoResult)
+	}
+}
+
+func TestNewPropoDrawFromMap(t *testing.T) {
+	m := map[string]interface{}{
+		"drawTime": "23-12-2017T16:00:00",
+		"drawNo":   float64(201751),
+		"results":  []interface{}{"2", "X", "1"},
+	}
+	want := &PropoDraw{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751, Results: []string{"2", "X", "1"}}
+	got, err := NewPropoDrawFromMap(m)
+	if err != nil {
+		t.Fatal("NewPropoDrawFromMap returned err:", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewPropoDrawFromMap(%v) \nhave: %#v\nwant: %#v", m, got, want)
+	}
+
+	m["results"] = []interface{}{"2", 1}
+	if _, err := NewPropoDrawFromMap(m); err == nil {
+		t.Errorf("NewPropoDrawFromMap(%v) expected error", m)
+	}
+	delete(m, "drawNo")
+	if _, err := NewPropoDrawFromMap(m); err == nil {
+		t.Errorf("NewPropoDrawFromMap(%v) expected error", m)
+	}
+}
+
+func TestPropoDraw_BeforeAfter(t *testing.T) {
+	earlier := &PropoDraw{DrawTime: "16-12-2017T16:00:00"}
+	later := PropoDraw{DrawTime: "23-12-2017T16:00:00"}
+
+	if before, err := earlier.Before(later); err != nil || !before {
+		t.Errorf("PropoDraw.Before(later) = %v, %v, want true, nil", before, err)
+	}
+	if after, err := earlier.After(later); err != nil || after {
+		t.Errorf("PropoDraw.After(later) = %v, %v, want false, nil", after, err)
+	}
+	if _, err := earlier.After(PropoDraw{DrawTime: "foo"}); err == nil {
+		t.Error("PropoDraw.After with invalid DrawTime expected error")
+	}
+}
+
+func TestSortPropoDrawsByTime(t *testing.T) {
+	draws := []PropoDraw{
+		{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751},
+		{DrawTime: "16-12-2017T16:00:00", DrawNo: 201750},
+	}
+	if err := SortPropoDrawsByTime(draws); err != nil {
+		t.Fatal("SortPropoDrawsByTime returned err:", err)
+	}
+	if draws[0].DrawNo != 201750 || draws[1].DrawNo != 201751 {
+		t.Errorf("SortPropoDrawsByTime order = %d, %d, want 201750, 201751", draws[0].DrawNo, draws[1].DrawNo)
+	}
+	if err := SortPropoDrawsByTime([]PropoDraw{{DrawTime: "foo"}}); err == nil {
+		t.Error("SortPropoDrawsByTime with invalid DrawTime expected error")
+	}
+}
+
+func TestDrawService_Propo_unknownGame(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %v %v", r.Method, r.URL)
+	})
+
+	var game PropoGame
+	if _, _, err := client.Draws.PropoLatest(game); err != ErrUnknownGame {
+		t.Errorf("client.Draws.PropoLatest(PropoGame{}) err = %v, want %v", err, ErrUnknownGame)
+	}
+	if _, _, err := client.Draws.PropoByNumber(game, 201751); err != ErrUnknownGame {
+		t.Errorf("client.Draws.PropoByNumber(PropoGame{}) err = %v, want %v", err, ErrUnknownGame)
+	}
+	if _, _, err := client.Draws.PropoByDate(game, 23, 12, 2017); err != ErrUnknownGame {
+		t.Errorf("client.Draws.PropoByDate(PropoGame{}) err = %v, want %v", err, ErrUnknownGame)
+	}
+}
@@ -0,0 +1,587 @@
+package opap
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultMaxConcurrency is the default maximum number of requests that the
+// draws service methods which fetch several draws or dates keep in flight at
+// the same time. It can be changed with the WithMaxConcurrency option.
+const defaultMaxConcurrency = 5
+
+// days returns every calendar day from start to end inclusive. The returned
+// days are set to midnight in the location of start.
+func days(start, end time.Time) []time.Time {
+	loc := start.Location()
+	from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	to := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc)
+
+	var dd []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		dd = append(dd, d)
+	}
+	return dd
+}
+
+// concurrently calls fn for each i from 0 to n-1, with at most limit calls
+// running at the same time. Once a call fails no new calls are started. It
+// waits for the started calls to return and reports the first error
+// encountered, if any.
+func concurrently(n, limit int, fn func(i int) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+	sem := make(chan struct{}, limit)
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		if failed() {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := fn(i); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// fetchDays calls fn concurrently for each of the days, keeping at most as
+// many calls running at the same time as the service allows.
+func (s *drawsService) fetchDays(dd []time.Time, fn func(day time.Time) error) error {
+	return concurrently(len(dd), s.maxConcurrency, func(i int) error {
+		return fn(dd[i])
+	})
+}
+
+// ByDateRangeSummary returns how many draws of game g took place on each day
+// from start to end inclusive, without returning the draws themselves. The
+// days are fetched concurrently and the map is keyed by midnight of each day
+// in the location of start. The requests are cancelled when ctx is done.
+func (s *drawsService) ByDateRangeSummary(ctx context.Context, g Game, start, end time.Time) (map[time.Time]int, error) {
+	var mu sync.Mutex
+	counts := make(map[time.Time]int)
+	err := s.fetchDays(days(start, end), func(day time.Time) error {
+		d, _, err := s.ByDateWithContext(ctx, g, day.Day(), int(day.Month()), day.Year())
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		counts[day] = len(d)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// ByDateRangeCSV fetches the draws of game g for each day from start to end
+// inclusive and writes each draw to w as a CSV record in the format of
+// Draw.MarshalCSV, as soon as the draws of its day arrive. The
+// days are fetched concurrently so the records are not in any particular
+// order. The requests are cancelled when ctx is done. It returns the number
+// of records written.
+func (s *drawsService) ByDateRangeCSV(ctx context.Context, g Game, start, end time.Time, w io.Writer) (int, error) {
+	var (
+		mu sync.Mutex
+		n  int
+	)
+	cw := csv.NewWriter(w)
+	err := s.fetchDays(days(start, end), func(day time.Time) error {
+		draws, _, err := s.ByDateWithContext(ctx, g, day.Day(), int(day.Month()), day.Year())
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for i := range draws {
+			rec, err := draws[i].MarshalCSV()
+			if err != nil {
+				return err
+			}
+			if err := cw.Write(rec); err != nil {
+				return err
+			}
+			n++
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+	return n, err
+}
+
+// ByDateRangeByWeekday returns the draws of game g for each day from start to
+// end inclusive that falls on one of weekdays. The days are fetched
+// concurrently and the draws are returned sorted by DrawNo. The requests are
+// cancelled when ctx is done.
+func (s *drawsService) ByDateRangeByWeekday(ctx context.Context, g Game, start, end time.Time, weekdays ...time.Weekday) ([]Draw, error) {
+	var dd []time.Time
+	for _, d := range days(start, end) {
+		for _, wd := range weekdays {
+			if d.Weekday() == wd {
+				dd = append(dd, d)
+				break
+			}
+		}
+	}
+
+	var acc DrawAccumulator
+	err := s.fetchDays(dd, func(day time.Time) error {
+		draws, _, err := s.ByDateWithContext(ctx, g, day.Day(), int(day.Month()), day.Year())
+		if err != nil {
+			return err
+		}
+		acc.Add(draws...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return acc.Slice(), nil
+}
+
+// DayError records the error of fetching the draws of a single day.
+type DayError struct {
+	Day time.Time
+	Err error
+}
+
+// DateRangeError is returned by ByDateRange and PropoByDateRange when the
+// draws of some of the days of the range could not be fetched. It lists the
+// failed days in chronological order.
+type DateRangeError []DayError
+
+func (e DateRangeError) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "fetching draws of %d days failed:", len(e))
+	for i, de := range e {
+		if i > 0 {
+			buf.WriteString(";")
+		}
+		fmt.Fprintf(&buf, " %s: %v", de.Day.Format("02-01-2006"), de.Err)
+	}
+	return buf.String()
+}
+
+// ByDateRange returns the draws of game g for each day from start to end
+// inclusive in chronological order, fetching one day at a time. It also
+// returns the responses of the requests it made. A draw close to midnight can
+// be returned for two consecutive days, so draws with the same DrawNo are
+// returned once, as they first appeared. If some of the days cannot be
+// fetched, it returns the draws of the rest of the days along with a
+// DateRangeError that lists the failed days. The draws can be limited with
+// WithMaxResults and returned in descending order with WithOrder, days can be
+// skipped with WithExcludedDates, and a fetch can be resumed with
+// WithCheckpoint and WithCheckpointCallback.
+func (s *drawsService) ByDateRange(g Game, start, end time.Time, opts ...DrawsOption) ([]Draw, []*http.Response, error) {
+	return s.byDateRange(context.Background(), g, start, end, nil, opts...)
+}
+
+// byDateRange is ByDateRange with requests that are cancelled when ctx is
+// done. If observe is not nil, it is called after the request of each day
+// with the response, which may be nil, and how long the request took.
+func (s *drawsService) byDateRange(ctx context.Context, g Game, start, end time.Time, observe func(day time.Time, resp *http.Response, d time.Duration), opts ...DrawsOption) ([]Draw, []*http.Response, error) {
+	o := newDrawsOptions(opts)
+	var (
+		draws     []Draw
+		responses []*http.Response
+		errs      DateRangeError
+		seen      = make(map[int]struct{})
+		latest    = o.checkpoint
+	)
+	for _, day := range days(start, end) {
+		if o.maxResults > 0 && len(draws) >= o.maxResults {
+			break
+		}
+		if o.isExcluded(day) {
+			continue
+		}
+		begin := time.Now()
+		d, resp, err := s.ByDateWithContext(ctx, g, day.Day(), int(day.Month()), day.Year())
+		if observe != nil {
+			observe(day, resp, time.Since(begin))
+		}
+		if resp != nil {
+			responses = append(responses, resp)
+		}
+		if err != nil {
+			errs = append(errs, DayError{Day: day, Err: err})
+			continue
+		}
+		added := false
+		for _, dr := range d {
+			if _, ok := seen[dr.DrawNo]; ok || dr.DrawNo <= o.checkpoint {
+				continue
+			}
+			seen[dr.DrawNo] = struct{}{}
+			draws = append(draws, dr)
+			if dr.DrawNo > latest {
+				latest = dr.DrawNo
+			}
+			added = true
+		}
+		if added && o.onCheckpoint != nil {
+			o.onCheckpoint(latest)
+		}
+	}
+	sort.SliceStable(draws, func(i, j int) bool { return draws[i].DrawNo < draws[j].DrawNo })
+	if o.maxResults > 0 && len(draws) > o.maxResults {
+		draws = draws[:o.maxResults]
+	}
+	if o.order == OrderDescending {
+		sort.SliceStable(draws, func(i, j int) bool { return draws[i].DrawNo > draws[j].DrawNo })
+	}
+	if len(errs) != 0 {
+		return draws, responses, errs
+	}
+	return draws, responses, nil
+}
+
+// RequestMetric describes the request that ByDateRangeWithMetrics made for
+// the draws of a single day. StatusCode is 0 if no response arrived, and
+// Cached reports whether the response was answered from the cache of the
+// client, as set up by WithCache.
+type RequestMetric struct {
+	Date       time.Time
+	Duration   time.Duration
+	StatusCode int
+	Cached     bool
+}
+
+// ByDateRangeWithMetrics is like ByDateRange but returns a RequestMetric for
+// each day from start to end inclusive, in chronological order, instead of
+// the responses. The requests are cancelled when ctx is done. It is meant for
+// performance debugging and for analysing how effective the cache is.
+func (s *drawsService) ByDateRangeWithMetrics(ctx context.Context, g Game, start, end time.Time) ([]Draw, []RequestMetric, error) {
+	var metrics []RequestMetric
+	draws, _, err := s.byDateRange(ctx, g, start, end, func(day time.Time, resp *http.Response, d time.Duration) {
+		m := RequestMetric{Date: day, Duration: d}
+		if resp != nil {
+			m.StatusCode = resp.StatusCode
+			m.Cached = resp.Header.Get(fromCacheHeader) != ""
+		}
+		metrics = append(metrics, m)
+	})
+	return draws, metrics, err
+}
+
+// PropoByDateRange is like ByDateRange for the Propo games.
+func (s *drawsService) PropoByDateRange(g PropoGame, start, end time.Time) ([]PropoDraw, []*http.Response, error) {
+	var (
+		draws     []PropoDraw
+		responses []*http.Response
+		errs      DateRangeError
+	)
+	for _, day := range days(start, end) {
+		d, resp, err := s.PropoByDate(g, day.Day(), int(day.Month()), day.Year())
+		if resp != nil {
+			responses = append(responses, resp)
+		}
+		if err != nil {
+			errs = append(errs, DayError{Day: day, Err: err})
+			continue
+		}
+		draws = append(draws, d...)
+	}
+	sort.SliceStable(draws, func(i, j int) bool { return draws[i].DrawNo < draws[j].DrawNo })
+	if len(errs) != 0 {
+		return draws, responses, errs
+	}
+	return draws, responses, nil
+}
+
+// ByNumberRange returns the draws of game g numbered from to to inclusive,
+// sorted by DrawNo. The draws are fetched concurrently. Draw numbers that the
+// service reports as not found, such as numbers of draws that have not taken
+// place yet, are skipped. Draws are numbered from 1, so a from below 1 is
+// treated as 1. Any other error stops the fetching and is returned. The
+// requests are cancelled when ctx is done.
+func (s *drawsService) ByNumberRange(ctx context.Context, g Game, from, to int) ([]Draw, error) {
+	return s.byNumberRange(ctx, g, from, to, nil)
+}
+
+// byNumberRange is ByNumberRange with done, if not nil, called once each draw
+// number has been fetched or found missing. done can be called by several
+// goroutines at the same time.
+func (s *drawsService) byNumberRange(ctx context.Context, g Game, from, to int, done func()) ([]Draw, error) {
+	if from < 1 {
+		from = 1
+	}
+	n := to - from + 1
+	if n < 0 {
+		n = 0
+	}
+	var acc DrawAccumulator
+	err := concurrently(n, s.maxConcurrency, func(i int) error {
+		d, _, err := s.ByNumberWithContext(ctx, g, from+i)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			acc.Add(*d)
+		}
+		if done != nil {
+			done()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return acc.Slice(), nil
+}
+
+// historyWarnSize is the number of draws above which FetchAllHistory warns
+// that it is about to fetch a long history.
+var historyWarnSize = 10000
+
+// FetchAllHistory returns every draw of game g, from the first draw up to the
+// latest one, sorted by DrawNo. The draws are fetched with ByNumberRange, so
+// draw numbers that the service reports as not found are skipped. If progress
+// is not nil, it is called with how many of the draw numbers have been
+// fetched so far and how many there are in total, once after each of them;
+// the calls are never made at the same time. If the Logger of the client is
+// also a Warner, it is warned when there are more than 10000 draws to fetch,
+// as a full Kino history takes hundreds of thousands of requests. The
+// requests are cancelled when ctx is done.
+func (s *drawsService) FetchAllHistory(ctx context.Context, g Game, progress func(fetched, total int)) ([]Draw, error) {
+	latest, _, err := s.latest(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+	total := latest.DrawNo
+	if w, ok := s.client.logger.(Warner); ok && total > historyWarnSize {
+		w.Warn(fmt.Sprintf("fetching the full history of %s: %d draws", g, total))
+	}
+	var done func()
+	if progress != nil {
+		var (
+			mu      sync.Mutex
+			fetched int
+		)
+		done = func() {
+			mu.Lock()
+			defer mu.Unlock()
+			fetched++
+			progress(fetched, total)
+		}
+	}
+	return s.byNumberRange(ctx, g, 1, total, done)
+}
+
+// monthDays returns the days of month of year up to today, as drawDate does
+// not accept days in the future. It returns an error if month is not valid.
+func monthDays(month, year int) ([]time.Time, error) {
+	if month < 1 || month > 12 {
+		return nil, fmt.Errorf("invalid month %d", month)
+	}
+	first := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+	y, m, d := now().Date()
+	if today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC); last.After(today) {
+		last = today
+	}
+	return days(first, last), nil
+}
+
+// ByMonth returns the draws of game g that took place in month of year,
+// sorted by DrawTime as parsed by the DrawTimeParser of the client. The days
+// of the month up to today are fetched concurrently. Days without draws,
+// including days the service reports as not found, are omitted, and a draw
+// returned for two days is kept once. Any other error stops the fetching and
+// is returned.
+func (s *drawsService) ByMonth(g Game, month, year int) ([]Draw, error) {
+	return s.byMonth(context.Background(), g, month, year)
+}
+
+// byMonth is ByMonth with the requests cancelled when ctx is done.
+func (s *drawsService) byMonth(ctx context.Context, g Game, month, year int) ([]Draw, error) {
+	var acc DrawAccumulator
+	err := s.fetchMonth(month, year, func(day time.Time) error {
+		d, _, err := s.ByDateWithContext(ctx, g, day.Day(), int(day.Month()), day.Year())
+		if err != nil {
+			return err
+		}
+		acc.AddUnique(d...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	draws := acc.Slice()
+	if err := sortDrawsByTime(draws, s.timeParser.Parse); err != nil {
+		return nil, err
+	}
+	return draws, nil
+}
+
+// fetchMonth calls fetch concurrently for each of the days of month of year
+// up to today, as fetchDays does. Days that fetch reports as not found are
+// skipped. It returns an error if month is not valid.
+func (s *drawsService) fetchMonth(month, year int, fetch func(day time.Time) error) error {
+	dd, err := monthDays(month, year)
+	if err != nil {
+		return err
+	}
+	return s.fetchDays(dd, func(day time.Time) error {
+		if err := fetch(day); err != nil && !IsNotFound(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+// MonthlyDrawStats holds statistics of the draws of a game that took place in
+// a month, as computed by MonthlyStats. The sums and numbers are those of the
+// main results of the draws, that is the results without a joker or bonus
+// number.
+type MonthlyDrawStats struct {
+	Month     time.Month
+	DrawCount int
+	// AverageSum and StdDev are the mean and the population standard
+	// deviation of the sums of the draws.
+	AverageSum float64
+	StdDev     float64
+	// MostFrequentNumber is the number drawn most often in the month, the
+	// lowest of them if several are drawn equally often.
+	MostFrequentNumber int
+}
+
+// MonthlyStats returns the MonthlyDrawStats of the draws of game g for each
+// month of year, from January to December, as fetched by ByMonth one month
+// after the other. Months without draws, including months that have not come
+// yet, have only their Month set. It returns ErrUnknownGame if g is not one
+// of the SupportedGames. Any error fetching the draws of a month stops the
+// fetching and is returned. The requests are cancelled when ctx is done.
+func (s *drawsService) MonthlyStats(ctx context.Context, g Game, year int) ([]MonthlyDrawStats, error) {
+	if _, err := InfoFor(g); err != nil {
+		return nil, err
+	}
+	stats := make([]MonthlyDrawStats, 12)
+	for i := range stats {
+		month := time.Month(i + 1)
+		draws, err := s.byMonth(ctx, g, int(month), year)
+		if err != nil {
+			return nil, err
+		}
+		stats[i] = monthlyDrawStats(g, month, draws)
+	}
+	return stats, nil
+}
+
+// monthlyDrawStats computes the MonthlyDrawStats of the draws of game g that
+// took place in month.
+func monthlyDrawStats(g Game, month time.Month, draws []Draw) MonthlyDrawStats {
+	ms := MonthlyDrawStats{Month: month, DrawCount: len(draws)}
+	if len(draws) == 0 {
+		return ms
+	}
+	freq := make(map[int]int)
+	var total float64
+	for i := range draws {
+		total += float64(draws[i].mainSum(g))
+		for _, n := range draws[i].mainResults(g) {
+			freq[n]++
+		}
+	}
+	ms.AverageSum = total / float64(len(draws))
+	var variance float64
+	for i := range draws {
+		diff := float64(draws[i].mainSum(g)) - ms.AverageSum
+		variance += diff * diff
+	}
+	ms.StdDev = math.Sqrt(variance / float64(len(draws)))
+	best := 0
+	for n, c := range freq {
+		if c > best || (c == best && n < ms.MostFrequentNumber) {
+			ms.MostFrequentNumber, best = n, c
+		}
+	}
+	return ms
+}
+
+// PropoByMonth is like ByMonth for the Propo games.
+func (s *drawsService) PropoByMonth(g PropoGame, month, year int) ([]PropoDraw, error) {
+	var acc PropoDrawAccumulator
+	err := s.fetchMonth(month, year, func(day time.Time) error {
+		d, _, err := s.PropoByDate(g, day.Day(), int(day.Month()), day.Year())
+		if err != nil {
+			return err
+		}
+		acc.AddUnique(d...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	draws := acc.Slice()
+	if err := sortPropoDrawsByTime(draws, s.timeParser.Parse); err != nil {
+		return nil, err
+	}
+	return draws, nil
+}
+
+// ByDateRangeForAllGames calls ByDateRange for each of the SupportedGames
+// concurrently, keeping at most as many games in flight as the service
+// allows. It returns the draws of each game along with the error, if any, of
+// each game that failed; a game whose days partly failed has both. A failed
+// game does not stop the others. The requests are cancelled when ctx is done,
+// in which case games not yet started are left out of both maps and the
+// error of ctx is returned.
+func (s *drawsService) ByDateRangeForAllGames(ctx context.Context, start, end time.Time) (map[Game][]Draw, map[Game]error, error) {
+	var (
+		mu    sync.Mutex
+		draws = make(map[Game][]Draw)
+		errs  = make(map[Game]error)
+	)
+	games := AllGames()
+	err := concurrently(len(games), s.maxConcurrency, func(i int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		g := games[i]
+		d, _, err := s.byDateRange(ctx, g, start, end, nil)
+		mu.Lock()
+		defer mu.Unlock()
+		if len(d) != 0 {
+			draws[g] = d
+		}
+		if err != nil {
+			errs[g] = err
+		}
+		return nil
+	})
+	return draws, errs, err
+}
@@ -0,0 +1,951 @@
+package opap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDays(t *testing.T) {
+	start := time.Date(2017, 12, 30, 22, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	want := []time.Time{
+		time.Date(2017, 12, 30, 0, 0, 0, 0, time.UTC),
+		time.Date(2017, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if got := days(start, end); !reflect.DeepEqual(got, want) {
+		t.Errorf("days(%v, %v) \nhave: %v\nwant: %v", start, end, got, want)
+	}
+
+	if got := days(end, start); len(got) != 0 {
+		t.Errorf("days(%v, %v) = %v, want no days", end, start, got)
+	}
+}
+
+func TestDrawService_ByDateRangeSummary(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/24-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1,24,15,8]}]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/25-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draws":{"draw":[]}}`)
+	})
+
+	var game Game = Joker
+	start := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 25, 0, 0, 0, 0, time.UTC)
+	got, err := client.Draws.ByDateRangeSummary(context.Background(), game, start, end)
+	if err != nil {
+		t.Fatal("client.Draws.ByDateRangeSummary returned err:", err)
+	}
+	want := map[time.Time]int{start: 1, end: 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByDateRangeSummary(%q, %v, %v) \nhave: %#v\nwant: %#v", game, start, end, got, want)
+	}
+}
+
+func TestDrawService_ByDateRangeSummary_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/24-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	var game Game = Joker
+	day := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	_, err := client.Draws.ByDateRangeSummary(context.Background(), game, day, day)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	testErrorResponse(t, err, 500)
+}
+
+func TestDrawService_ByDateRangeCSV(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/24-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1,24,15,8]}]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/25-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draws":{"draw":[]}}`)
+	})
+
+	var game Game = Joker
+	start := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 25, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	n, err := client.Draws.ByDateRangeCSV(context.Background(), game, start, end, &buf)
+	if err != nil {
+		t.Fatal("client.Draws.ByDateRangeCSV returned err:", err)
+	}
+	if got, want := n, 1; got != want {
+		t.Errorf("client.Draws.ByDateRangeCSV(%q, %v, %v) wrote %d records, want %d", game, start, end, got, want)
+	}
+	want := "24-12-2017T22:00:00,1873,40,13,1,24,15,8\n"
+	if got := buf.String(); got != want {
+		t.Errorf("client.Draws.ByDateRangeCSV(%q, %v, %v) \nhave: %q\nwant: %q", game, start, end, got, want)
+	}
+}
+
+func TestDrawService_ByDateRangeCSV_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/24-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	var game Game = Joker
+	day := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	_, err := client.Draws.ByDateRangeCSV(context.Background(), game, day, day, &buf)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	testErrorResponse(t, err, 500)
+}
+
+func TestDrawService_ByDateRangeByWeekday(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/lotto/drawDate/20-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"20-12-2017T21:30:00","drawNo":1853,"results":[3,10,23,38,41,44,7]}]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/lotto/drawDate/23-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"23-12-2017T21:30:00","drawNo":1854,"results":[1,5,12,20,27,46,17]}]}}`)
+	})
+	// Every other day of the range is not requested and would be reported
+	// as a 404 by the mux, failing the call.
+
+	var game Game = Lotto
+	start := time.Date(2017, 12, 18, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	got, err := client.Draws.ByDateRangeByWeekday(context.Background(), game, start, end, time.Wednesday, time.Saturday)
+	if err != nil {
+		t.Fatal("client.Draws.ByDateRangeByWeekday returned err:", err)
+	}
+	want := []Draw{
+		{DrawTime: "20-12-2017T21:30:00", DrawNo: 1853, Results: []int{3, 10, 23, 38, 41, 44, 7}},
+		{DrawTime: "23-12-2017T21:30:00", DrawNo: 1854, Results: []int{1, 5, 12, 20, 27, 46, 17}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByDateRangeByWeekday(%q, %v, %v) \nhave: %#v\nwant: %#v", game, start, end, got, want)
+	}
+}
+
+func TestDrawService_ByDateRangeByWeekday_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/lotto/drawDate/20-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	var game Game = Lotto
+	day := time.Date(2017, 12, 20, 0, 0, 0, 0, time.UTC)
+	_, err := client.Draws.ByDateRangeByWeekday(context.Background(), game, day, day, time.Wednesday)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	testErrorResponse(t, err, 500)
+}
+
+func TestDrawService_ByDateRange(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/21-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"21-12-2017T22:00:00","drawNo":1872,"results":[2,12,17,31,44,3]}]}}`)
+	})
+	for _, date := range []string{"22-12-2017", "23-12-2017"} {
+		mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/"+date+".json", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			fmt.Fprint(w, `{"draws":{"draw":[]}}`)
+		})
+	}
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/24-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1,24,15,8]}]}}`)
+	})
+
+	var game Game = Joker
+	start := time.Date(2017, 12, 21, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	got, responses, err := client.Draws.ByDateRange(game, start, end)
+	if err != nil {
+		t.Fatal("client.Draws.ByDateRange returned err:", err)
+	}
+	want := []Draw{
+		{DrawTime: "21-12-2017T22:00:00", DrawNo: 1872, Results: []int{2, 12, 17, 31, 44, 3}},
+		{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByDateRange(%q, %v, %v) \nhave: %#v\nwant: %#v", game, start, end, got, want)
+	}
+	if got, want := len(responses), 4; got != want {
+		t.Errorf("client.Draws.ByDateRange returned %d responses, want %d", got, want)
+	}
+}
+
+func TestDrawService_ByDateRange_partialFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/21-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/22-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"22-12-2017T22:00:00","drawNo":1872,"results":[2,12,17,31,44,3]}]}}`)
+	})
+
+	var game Game = Joker
+	start := time.Date(2017, 12, 21, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 23, 0, 0, 0, 0, time.UTC)
+	got, _, err := client.Draws.ByDateRange(game, start, end)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	rangeErr, ok := err.(DateRangeError)
+	if !ok {
+		t.Fatalf("client.Draws.ByDateRange err type = %T, want DateRangeError", err)
+	}
+	var failed []time.Time
+	for _, de := range rangeErr {
+		failed = append(failed, de.Day)
+	}
+	wantFailed := []time.Time{start, end} // 23-12-2017 is not handled by the mux
+	if !reflect.DeepEqual(failed, wantFailed) {
+		t.Errorf("DateRangeError days = %v, want %v", failed, wantFailed)
+	}
+	want := []Draw{{DrawTime: "22-12-2017T22:00:00", DrawNo: 1872, Results: []int{2, 12, 17, 31, 44, 3}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByDateRange(%q, %v, %v) \nhave: %#v\nwant: %#v", game, start, end, got, want)
+	}
+}
+
+func TestDrawService_ByDateRange_dedup(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/kino/drawDate/23-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"23-12-2017T23:55:00","drawNo":639000,"results":[1,2,3]}]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/kino/drawDate/24-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"24-12-2017T00:00:00","drawNo":639000,"results":[4,5,6]},{"drawTime":"24-12-2017T09:00:00","drawNo":639001,"results":[7,8,9]}]}}`)
+	})
+
+	var game Game = Kino
+	start := time.Date(2017, 12, 23, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	got, _, err := client.Draws.ByDateRange(game, start, end)
+	if err != nil {
+		t.Fatal("client.Draws.ByDateRange returned err:", err)
+	}
+	want := []Draw{
+		{DrawTime: "23-12-2017T23:55:00", DrawNo: 639000, Results: []int{1, 2, 3}},
+		{DrawTime: "24-12-2017T09:00:00", DrawNo: 639001, Results: []int{7, 8, 9}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByDateRange(%q, %v, %v) \nhave: %#v\nwant: %#v", game, start, end, got, want)
+	}
+}
+
+func TestDrawService_ByDateRange_withMaxResults(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/kino/drawDate/23-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"23-12-2017T09:05:00","drawNo":638801},{"drawTime":"23-12-2017T09:00:00","drawNo":638800}]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/kino/drawDate/24-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"24-12-2017T09:05:00","drawNo":639001},{"drawTime":"24-12-2017T09:00:00","drawNo":639000}]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/kino/drawDate/25-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request for 25-12-2017 after reaching the max results")
+	})
+
+	var game Game = Kino
+	start := time.Date(2017, 12, 23, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 25, 0, 0, 0, 0, time.UTC)
+	got, responses, err := client.Draws.ByDateRange(game, start, end, WithMaxResults(3))
+	if err != nil {
+		t.Fatal("client.Draws.ByDateRange returned err:", err)
+	}
+	want := []Draw{
+		{DrawTime: "23-12-2017T09:00:00", DrawNo: 638800},
+		{DrawTime: "23-12-2017T09:05:00", DrawNo: 638801},
+		{DrawTime: "24-12-2017T09:00:00", DrawNo: 639000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByDateRange(%q, %v, %v, WithMaxResults(3)) \nhave: %#v\nwant: %#v", game, start, end, got, want)
+	}
+	if got, want := len(responses), 2; got != want {
+		t.Errorf("client.Draws.ByDateRange returned %d responses, want %d", got, want)
+	}
+}
+
+func TestDrawService_ByDateRange_withOrder(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/kino/drawDate/23-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"23-12-2017T09:00:00","drawNo":638800},{"drawTime":"23-12-2017T09:05:00","drawNo":638801}]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/kino/drawDate/24-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"24-12-2017T09:00:00","drawNo":639000},{"drawTime":"24-12-2017T09:05:00","drawNo":639001}]}}`)
+	})
+
+	var game Game = Kino
+	start := time.Date(2017, 12, 23, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	got, _, err := client.Draws.ByDateRange(game, start, end, WithOrder(OrderDescending), WithMaxResults(3))
+	if err != nil {
+		t.Fatal("client.Draws.ByDateRange returned err:", err)
+	}
+	// The max results keep the first draws by DrawNo before they are
+	// reversed.
+	want := []Draw{
+		{DrawTime: "24-12-2017T09:00:00", DrawNo: 639000},
+		{DrawTime: "23-12-2017T09:05:00", DrawNo: 638801},
+		{DrawTime: "23-12-2017T09:00:00", DrawNo: 638800},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByDateRange(%q, %v, %v, WithOrder(OrderDescending)) \nhave: %#v\nwant: %#v", game, start, end, got, want)
+	}
+}
+
+func TestDrawService_ByDateRange_withExcludedDates(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/24-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"24-12-2017T22:00:00","drawNo":1873}]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/25-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request for the excluded 25-12-2017")
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/26-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[]}}`)
+	})
+
+	var game Game = Joker
+	start := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 26, 0, 0, 0, 0, time.UTC)
+	christmas := time.Date(2017, 12, 25, 18, 30, 0, 0, time.UTC)
+	got, responses, err := client.Draws.ByDateRange(game, start, end, WithExcludedDates(christmas))
+	if err != nil {
+		t.Fatal("client.Draws.ByDateRange returned err:", err)
+	}
+	want := []Draw{{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByDateRange(%q, %v, %v, WithExcludedDates(%v)) \nhave: %#v\nwant: %#v", game, start, end, christmas, got, want)
+	}
+	if got, want := len(responses), 2; got != want {
+		t.Errorf("client.Draws.ByDateRange returned %d responses, want %d", got, want)
+	}
+}
+
+func TestDrawService_ByDateRange_withCheckpoint(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/kino/drawDate/23-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"23-12-2017T09:00:00","drawNo":638800},{"drawTime":"23-12-2017T09:05:00","drawNo":638801}]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/kino/drawDate/24-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"24-12-2017T09:05:00","drawNo":639001},{"drawTime":"24-12-2017T09:00:00","drawNo":639000}]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/kino/drawDate/25-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[]}}`)
+	})
+
+	var checkpoints []int
+	var game Game = Kino
+	start := time.Date(2017, 12, 23, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 25, 0, 0, 0, 0, time.UTC)
+	got, _, err := client.Draws.ByDateRange(game, start, end,
+		WithCheckpoint(638801),
+		WithCheckpointCallback(func(latestDrawNo int) { checkpoints = append(checkpoints, latestDrawNo) }),
+	)
+	if err != nil {
+		t.Fatal("client.Draws.ByDateRange returned err:", err)
+	}
+	want := []Draw{
+		{DrawTime: "24-12-2017T09:00:00", DrawNo: 639000},
+		{DrawTime: "24-12-2017T09:05:00", DrawNo: 639001},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByDateRange(%q, %v, %v, WithCheckpoint(638801)) \nhave: %#v\nwant: %#v", game, start, end, got, want)
+	}
+	// Only 24-12-2017 brings draws after the checkpoint.
+	if want := []int{639001}; !reflect.DeepEqual(checkpoints, want) {
+		t.Errorf("WithCheckpointCallback calls = %v, want %v", checkpoints, want)
+	}
+}
+
+func TestDrawService_ByDateRangeWithMetrics(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/21-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"21-12-2017T22:00:00","drawNo":1872,"results":[2,12,17,31,44,3]}]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/drawDate/22-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	c := NewClient(WithBaseURL(client.BaseURL), WithCache(10, time.Hour))
+	var game Game = Joker
+	start := time.Date(2017, 12, 21, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 22, 0, 0, 0, 0, time.UTC)
+	for i, wantCached := range []bool{false, true} {
+		got, metrics, err := c.Draws.ByDateRangeWithMetrics(context.Background(), game, start, end)
+		if _, ok := err.(DateRangeError); !ok {
+			t.Fatalf("client.Draws.ByDateRangeWithMetrics err = %v, want DateRangeError", err)
+		}
+		want := []Draw{{DrawTime: "21-12-2017T22:00:00", DrawNo: 1872, Results: []int{2, 12, 17, 31, 44, 3}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("client.Draws.ByDateRangeWithMetrics(%q, %v, %v) \nhave: %#v\nwant: %#v", game, start, end, got, want)
+		}
+		if got, want := len(metrics), 2; got != want {
+			t.Fatalf("client.Draws.ByDateRangeWithMetrics returned %d metrics, want %d", got, want)
+		}
+		// Failed responses are not cached, so only the first day can be
+		// answered from the cache.
+		wantMetrics := []RequestMetric{
+			{Date: start, StatusCode: 200, Cached: wantCached},
+			{Date: end, StatusCode: 500},
+		}
+		for j, m := range metrics {
+			if m.Duration < 0 {
+				t.Errorf("call %d: metric %d Duration = %v, want >= 0", i, j, m.Duration)
+			}
+			m.Duration = 0
+			if m != wantMetrics[j] {
+				t.Errorf("call %d: metric %d = %+v, want %+v", i, j, m, wantMetrics[j])
+			}
+		}
+	}
+}
+
+func TestDrawService_PropoByDateRange(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/drawDate/23-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"23-12-2017T16:00:00","drawNo":201751,"results":["2","2","1"]}]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/drawDate/24-12-2017.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	var game PropoGame = PropoSat
+	start := time.Date(2017, 12, 23, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	got, responses, err := client.Draws.PropoByDateRange(game, start, end)
+	if _, ok := err.(DateRangeError); !ok {
+		t.Errorf("client.Draws.PropoByDateRange err = %v, want DateRangeError", err)
+	}
+	want := []PropoDraw{{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751, Results: []string{"2", "2", "1"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.PropoByDateRange(%q, %v, %v) \nhave: %#v\nwant: %#v", game, start, end, got, want)
+	}
+	if got, want := len(responses), 2; got != want {
+		t.Errorf("client.Draws.PropoByDateRange returned %d responses, want %d", got, want)
+	}
+}
+
+func TestDateRangeError_Error(t *testing.T) {
+	err := DateRangeError{
+		{Day: time.Date(2017, 12, 21, 0, 0, 0, 0, time.UTC), Err: fmt.Errorf("foo")},
+		{Day: time.Date(2017, 12, 23, 0, 0, 0, 0, time.UTC), Err: fmt.Errorf("bar")},
+	}
+	want := "fetching draws of 2 days failed: 21-12-2017: foo; 23-12-2017: bar"
+	if got := err.Error(); got != want {
+		t.Errorf("DateRangeError.Error() = %q, want %q", got, want)
+	}
+}
+
+func TestConcurrently(t *testing.T) {
+	var running, maxRunning, calls int32
+	err := concurrently(20, 3, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	if err != nil {
+		t.Fatal("concurrently returned err:", err)
+	}
+	if got, want := calls, int32(20); got != want {
+		t.Errorf("concurrently made %d calls, want %d", got, want)
+	}
+	if got := maxRunning; got > 3 {
+		t.Errorf("concurrently ran %d calls at the same time, want at most 3", got)
+	}
+}
+
+func TestConcurrently_stopsOnError(t *testing.T) {
+	var calls int32
+	err := concurrently(100, 1, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 2 {
+			return fmt.Errorf("call %d failed", i)
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got := atomic.LoadInt32(&calls); got >= 100 {
+		t.Errorf("concurrently made %d calls after an error, want it to stop early", got)
+	}
+}
+
+func TestDrawService_ByNumberRange(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/1872.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draw":{"drawTime":"21-12-2017T22:00:00","drawNo":1872,"results":[2,12,17,31,44,3]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/1873.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draw":{"drawTime":"24-12-2017T22:00:00","drawNo":1873,"results":[40,13,1,24,15,8]}}`)
+	})
+	// 1874 is not handled by the mux and is reported as not found.
+
+	var game Game = Joker
+	got, err := client.Draws.ByNumberRange(context.Background(), game, 1872, 1874)
+	if err != nil {
+		t.Fatal("client.Draws.ByNumberRange returned err:", err)
+	}
+	want := []Draw{
+		{DrawTime: "21-12-2017T22:00:00", DrawNo: 1872, Results: []int{2, 12, 17, 31, 44, 3}},
+		{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByNumberRange(%q, 1872, 1874) \nhave: %#v\nwant: %#v", game, got, want)
+	}
+}
+
+func TestDrawService_ByNumberRange_fromBelowOne(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/1.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draw":{"drawTime":"03-01-2016T22:00:00","drawNo":1,"results":[3,19,27,34,41,7]}}`)
+	})
+	// 2 is not handled by the mux and is reported as not found.
+
+	var game Game = Joker
+	got, err := client.Draws.ByNumberRange(context.Background(), game, 0, 2)
+	if err != nil {
+		t.Fatal("client.Draws.ByNumberRange returned err:", err)
+	}
+	want := []Draw{
+		{DrawTime: "03-01-2016T22:00:00", DrawNo: 1, Results: []int{3, 19, 27, 34, 41, 7}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByNumberRange(%q, 0, 2) \nhave: %#v\nwant: %#v", game, got, want)
+	}
+}
+
+func TestDrawService_FetchAllHistory(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draw":{"drawTime":"10-01-2016T22:00:00","drawNo":3,"results":[5,9,22,30,44,1]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/1.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draw":{"drawTime":"03-01-2016T22:00:00","drawNo":1,"results":[3,19,27,34,41,7]}}`)
+	})
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/3.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"draw":{"drawTime":"10-01-2016T22:00:00","drawNo":3,"results":[5,9,22,30,44,1]}}`)
+	})
+	// 2 is not handled by the mux and is reported as not found.
+
+	var calls [][2]int
+	progress := func(fetched, total int) {
+		calls = append(calls, [2]int{fetched, total})
+	}
+	var game Game = Joker
+	got, err := client.Draws.FetchAllHistory(context.Background(), game, progress)
+	if err != nil {
+		t.Fatal("client.Draws.FetchAllHistory returned err:", err)
+	}
+	want := []Draw{
+		{DrawTime: "03-01-2016T22:00:00", DrawNo: 1, Results: []int{3, 19, 27, 34, 41, 7}},
+		{DrawTime: "10-01-2016T22:00:00", DrawNo: 3, Results: []int{5, 9, 22, 30, 44, 1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.FetchAllHistory(%q) \nhave: %#v\nwant: %#v", game, got, want)
+	}
+	if wantCalls := [][2]int{{1, 3}, {2, 3}, {3, 3}}; !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("client.Draws.FetchAllHistory(%q) progress calls = %v, want %v", game, calls, wantCalls)
+	}
+}
+
+func TestDrawService_FetchAllHistory_warn(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orig := historyWarnSize
+	historyWarnSize = 1
+	defer func() { historyWarnSize = orig }()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draw":{"drawNo":2}}`)
+	})
+
+	l := new(warningLogger)
+	c := NewClient(WithBaseURL(client.BaseURL), WithLogger(l))
+	if _, err := c.Draws.FetchAllHistory(context.Background(), Joker, nil); err != nil {
+		t.Fatal("FetchAllHistory returned err:", err)
+	}
+	want := []string{"fetching the full history of JOKER: 2 draws"}
+	if !reflect.DeepEqual(l.warnings, want) {
+		t.Errorf("FetchAllHistory warnings = %q, want %q", l.warnings, want)
+	}
+}
+
+func TestDrawService_FetchAllHistory_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	_, err := client.Draws.FetchAllHistory(context.Background(), Joker, nil)
+	testErrorResponse(t, err, 500)
+}
+
+func TestDrawService_ByNumberRange_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/1873.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	var game Game = Joker
+	_, err := client.Draws.ByNumberRange(context.Background(), game, 1872, 1874)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	testErrorResponse(t, err, 500)
+}
+
+func TestDrawService_ranges_cancelled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %v %v", r.Method, r.URL)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var game Game = Joker
+	day := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	if _, err := client.Draws.ByDateRangeSummary(ctx, game, day, day); !errors.Is(err, context.Canceled) {
+		t.Errorf("client.Draws.ByDateRangeSummary with cancelled ctx err = %v, want %v", err, context.Canceled)
+	}
+	if _, err := client.Draws.ByDateRangeCSV(ctx, game, day, day, ioutil.Discard); !errors.Is(err, context.Canceled) {
+		t.Errorf("client.Draws.ByDateRangeCSV with cancelled ctx err = %v, want %v", err, context.Canceled)
+	}
+	if _, err := client.Draws.ByDateRangeByWeekday(ctx, game, day, day, time.Sunday); !errors.Is(err, context.Canceled) {
+		t.Errorf("client.Draws.ByDateRangeByWeekday with cancelled ctx err = %v, want %v", err, context.Canceled)
+	}
+	if _, err := client.Draws.ByNumberRange(ctx, game, 1873, 1873); !errors.Is(err, context.Canceled) {
+		t.Errorf("client.Draws.ByNumberRange with cancelled ctx err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestMonthDays(t *testing.T) {
+	orig := now
+	now = func() time.Time { return time.Date(2018, 1, 9, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = orig }()
+
+	tests := []struct {
+		month, year int
+		want        int
+	}{
+		{2, 2016, 29},
+		{2, 2017, 28},
+		{12, 2017, 31},
+		{4, 2017, 30},
+		{1, 2018, 9},
+		{2, 2018, 0},
+	}
+	for _, tt := range tests {
+		dd, err := monthDays(tt.month, tt.year)
+		if err != nil {
+			t.Errorf("monthDays(%d, %d) returned err: %v", tt.month, tt.year, err)
+		}
+		if got := len(dd); got != tt.want {
+			t.Errorf("monthDays(%d, %d) returned %d days, want %d", tt.month, tt.year, got, tt.want)
+		}
+	}
+	for _, month := range []int{0, 13} {
+		if _, err := monthDays(month, 2017); err == nil {
+			t.Errorf("monthDays(%d, 2017) expected error", month)
+		}
+	}
+}
+
+func TestDrawService_ByMonth(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + defaultDrawsEndpoint + "/joker/drawDate/24-02-2016.json":
+			fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"24-02-2016T22:00:00","drawNo":1700}]}}`)
+		case "/" + defaultDrawsEndpoint + "/joker/drawDate/29-02-2016.json":
+			fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"28-02-2016T22:00:00","drawNo":1701}]}}`)
+		case "/" + defaultDrawsEndpoint + "/joker/drawDate/28-02-2016.json":
+			fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"28-02-2016T22:00:00","drawNo":1701}]}}`)
+		case "/" + defaultDrawsEndpoint + "/joker/drawDate/01-02-2016.json":
+			fmt.Fprint(w, `{"draws":{"draw":[]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	var game Game = Joker
+	got, err := client.Draws.ByMonth(game, 2, 2016)
+	if err != nil {
+		t.Fatal("client.Draws.ByMonth returned err:", err)
+	}
+	want := []Draw{
+		{DrawTime: "24-02-2016T22:00:00", DrawNo: 1700},
+		{DrawTime: "28-02-2016T22:00:00", DrawNo: 1701},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.ByMonth(%q, 2, 2016) \nhave: %#v\nwant: %#v", game, got, want)
+	}
+}
+
+func TestDrawService_ByMonth_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	_, err := client.Draws.ByMonth(Joker, 12, 2017)
+	testErrorResponse(t, err, 500)
+}
+
+func TestDrawService_MonthlyStats(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orig := now
+	defer func() { now = orig }()
+	now = func() time.Time { return time.Date(2016, 3, 31, 12, 0, 0, 0, time.UTC) }
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + defaultDrawsEndpoint + "/joker/drawDate/24-02-2016.json":
+			fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"24-02-2016T22:00:00","drawNo":1700,"results":[1,2,3,4,5,9]}]}}`)
+		case "/" + defaultDrawsEndpoint + "/joker/drawDate/28-02-2016.json":
+			fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"28-02-2016T22:00:00","drawNo":1701,"results":[1,2,3,4,25,9]}]}}`)
+		case "/" + defaultDrawsEndpoint + "/joker/drawDate/02-03-2016.json":
+			fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"02-03-2016T22:00:00","drawNo":1702,"results":[40,10,20,30,45,1]}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	var game Game = Joker
+	got, err := client.Draws.MonthlyStats(context.Background(), game, 2016)
+	if err != nil {
+		t.Fatal("client.Draws.MonthlyStats returned err:", err)
+	}
+	want := make([]MonthlyDrawStats, 12)
+	for i := range want {
+		want[i].Month = time.Month(i + 1)
+	}
+	// The joker numbers are left out of the sums and the frequencies.
+	want[1] = MonthlyDrawStats{Month: time.February, DrawCount: 2, AverageSum: 25, StdDev: 10, MostFrequentNumber: 1}
+	want[2] = MonthlyDrawStats{Month: time.March, DrawCount: 1, AverageSum: 145, StdDev: 0, MostFrequentNumber: 10}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.MonthlyStats(%q, 2016) \nhave: %#v\nwant: %#v", game, got, want)
+	}
+}
+
+func TestDrawService_MonthlyStats_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orig := now
+	defer func() { now = orig }()
+	now = func() time.Time { return time.Date(2016, 1, 5, 12, 0, 0, 0, time.UTC) }
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "something broke", 500)
+	})
+
+	_, err := client.Draws.MonthlyStats(context.Background(), Joker, 2016)
+	testErrorResponse(t, err, 500)
+
+	if _, err := client.Draws.MonthlyStats(context.Background(), Game("foo"), 2016); err != ErrUnknownGame {
+		t.Errorf("client.Draws.MonthlyStats(\"foo\") err = %v, want %v", err, ErrUnknownGame)
+	}
+}
+
+func TestDrawService_PropoByMonth(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + defaultDrawsEndpoint + "/proposat/drawDate/23-12-2017.json":
+			fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"23-12-2017T16:00:00","drawNo":201751}]}}`)
+		case "/" + defaultDrawsEndpoint + "/proposat/drawDate/02-12-2017.json":
+			fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"02-12-2017T16:00:00","drawNo":201748}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	var game PropoGame = PropoSat
+	got, err := client.Draws.PropoByMonth(game, 12, 2017)
+	if err != nil {
+		t.Fatal("client.Draws.PropoByMonth returned err:", err)
+	}
+	want := []PropoDraw{
+		{DrawTime: "02-12-2017T16:00:00", DrawNo: 201748},
+		{DrawTime: "23-12-2017T16:00:00", DrawNo: 201751},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("client.Draws.PropoByMonth(%q, 12, 2017) \nhave: %#v\nwant: %#v", game, got, want)
+	}
+}
+
+func TestDrawService_ByDateRangeForAllGames(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + defaultDrawsEndpoint + "/joker/drawDate/24-12-2017.json":
+			fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"24-12-2017T22:00:00","drawNo":1873}]}}`)
+		case "/" + defaultDrawsEndpoint + "/lotto/drawDate/23-12-2017.json":
+			fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"23-12-2017T21:30:00","drawNo":1790}]}}`)
+		case "/" + defaultDrawsEndpoint + "/kino/drawDate/23-12-2017.json":
+			http.Error(w, "something broke", 500)
+		default:
+			fmt.Fprint(w, `{"draws":{"draw":[]}}`)
+		}
+	})
+
+	start := time.Date(2017, 12, 23, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	draws, errs, err := client.Draws.ByDateRangeForAllGames(context.Background(), start, end)
+	if err != nil {
+		t.Fatal("client.Draws.ByDateRangeForAllGames returned err:", err)
+	}
+	wantDraws := map[Game][]Draw{
+		Joker: {{DrawTime: "24-12-2017T22:00:00", DrawNo: 1873}},
+		Lotto: {{DrawTime: "23-12-2017T21:30:00", DrawNo: 1790}},
+	}
+	if !reflect.DeepEqual(draws, wantDraws) {
+		t.Errorf("client.Draws.ByDateRangeForAllGames draws \nhave: %v\nwant: %v", draws, wantDraws)
+	}
+	if got, want := len(errs), 1; got != want {
+		t.Fatalf("client.Draws.ByDateRangeForAllGames returned %d game errors, want %d: %v", got, want, errs)
+	}
+	if _, ok := errs[Kino].(DateRangeError); !ok {
+		t.Errorf("client.Draws.ByDateRangeForAllGames kino err = %v, want DateRangeError", errs[Kino])
+	}
+}
+
+func TestDrawService_ByDateRangeForAllGames_cancelled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %v", r.URL)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	day := time.Date(2017, 12, 24, 0, 0, 0, 0, time.UTC)
+	draws, errs, err := client.Draws.ByDateRangeForAllGames(ctx, day, day)
+	if err != context.Canceled {
+		t.Errorf("client.Draws.ByDateRangeForAllGames err = %v, want %v", err, context.Canceled)
+	}
+	if len(draws) != 0 || len(errs) != 0 {
+		t.Errorf("client.Draws.ByDateRangeForAllGames = %v, %v, want no draws or game errors", draws, errs)
+	}
+}
+
+func TestDrawService_ByMonth_drawTimeParser(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + defaultDrawsEndpoint + "/joker/drawDate/21-12-2017.json":
+			fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"2017-12-21 22:00","drawNo":1872}]}}`)
+		case "/" + defaultDrawsEndpoint + "/joker/drawDate/24-12-2017.json":
+			fmt.Fprint(w, `{"draws":{"draw":[{"drawTime":"2017-12-24 22:00","drawNo":1873}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	if _, err := client.Draws.ByMonth(Joker, 12, 2017); err == nil {
+		t.Error("client.Draws.ByMonth with the default parser expected error")
+	}
+
+	c := NewClient(WithBaseURL(client.BaseURL), WithDrawTimeParser(DrawTimeParser{Layout: "2006-01-02 15:04"}))
+	got, err := c.Draws.ByMonth(Joker, 12, 2017)
+	if err != nil {
+		t.Fatal("client.Draws.ByMonth returned err:", err)
+	}
+	if len(got) != 2 || got[0].DrawNo != 1872 || got[1].DrawNo != 1873 {
+		t.Errorf("client.Draws.ByMonth = %v, want draws 1872 and 1873", got)
+	}
+}
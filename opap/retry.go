@@ -0,0 +1,95 @@
+package opap
+
+import (
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryTransport is an http.RoundTripper that retries requests which fail
+// with a transient error: a connection error or one of the status codes 429,
+// 500, 502, 503 and 504. Retries wait using exponential backoff with full
+// jitter, that is a random delay between zero and InitialDelay doubled for
+// every previous retry. A request whose context is done is not retried.
+type RetryTransport struct {
+	// Base is the transport that makes the requests. If nil,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	// MaxAttempts is the total number of attempts made for a request,
+	// including the first one. Values less than 2 disable retries.
+	MaxAttempts int
+
+	// InitialDelay is the upper bound of the delay before the first retry.
+	InitialDelay time.Duration
+}
+
+// jitter returns a random duration in [0, n). It is a variable so that tests
+// can make the backoff deterministic.
+var jitter = func(n int64) int64 { return rand.Int63n(n) }
+
+// retryStatus holds the status codes of responses that are retried.
+var retryStatus = map[int]bool{
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+}
+
+// RoundTrip implements http.RoundTripper. The request is not modified: each
+// retry sends a clone of it with a fresh body.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	// A request body can only be sent again if it can be recreated.
+	canRetry := req.Body == nil || req.GetBody != nil
+
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 {
+			r = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				r.Body = body
+			}
+		}
+
+		resp, err := base.RoundTrip(r)
+		if !canRetry || attempt >= t.MaxAttempts || req.Context().Err() != nil {
+			return resp, err
+		}
+		if err == nil && !retryStatus[resp.StatusCode] {
+			return resp, nil
+		}
+		if resp != nil {
+			// Drain the body so that the connection can be reused.
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(t.backoff(attempt))
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before the retry that follows attempt.
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	max := t.InitialDelay << uint(attempt-1)
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(jitter(int64(max)))
+}
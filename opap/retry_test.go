@@ -0,0 +1,214 @@
+package opap
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// maxJitter makes the backoff of RetryTransport use the upper bound of each
+// delay until the returned function is called.
+func maxJitter() (restore func()) {
+	orig := jitter
+	jitter = func(n int64) int64 { return n - 1 }
+	return func() { jitter = orig }
+}
+
+func TestRetryTransport_backoff(t *testing.T) {
+	defer maxJitter()()
+
+	rt := &RetryTransport{InitialDelay: 10 * time.Millisecond}
+	for attempt, want := range map[int]time.Duration{
+		1: 10*time.Millisecond - 1,
+		2: 20*time.Millisecond - 1,
+		3: 40*time.Millisecond - 1,
+	} {
+		if got := rt.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	rt = &RetryTransport{}
+	if got := rt.backoff(1); got != 0 {
+		t.Errorf("backoff(1) with zero InitialDelay = %v, want 0", got)
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	defer maxJitter()()
+
+	for _, status := range []int{429, 500, 502, 503, 504} {
+		var calls int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				http.Error(w, "try again", status)
+				return
+			}
+			fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+		}))
+		u, _ := url.Parse(srv.URL)
+		c := NewClient(WithBaseURL(u), WithRetry(3, 5*time.Millisecond))
+
+		start := time.Now()
+		d, _, err := c.Draws.Latest(Joker)
+		elapsed := time.Since(start)
+		srv.Close()
+
+		if err != nil {
+			t.Errorf("status %d: Latest returned err: %v", status, err)
+			continue
+		}
+		if got, want := d.DrawNo, 1873; got != want {
+			t.Errorf("status %d: DrawNo = %d, want %d", status, got, want)
+		}
+		if got, want := atomic.LoadInt32(&calls), int32(3); got != want {
+			t.Errorf("status %d: server called %d times, want %d", status, got, want)
+		}
+		// The two retries wait just under 5ms and 10ms.
+		if min := 14 * time.Millisecond; elapsed < min {
+			t.Errorf("status %d: retries took %v, want at least %v", status, elapsed, min)
+		}
+	}
+}
+
+func TestRetryTransport_leavesRequestUntouched(t *testing.T) {
+	var (
+		calls  int32
+		bodies []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if atomic.AddInt32(&calls, 1) < 2 {
+			http.Error(w, "try again", 503)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequest("POST", srv.URL, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal("http.NewRequest returned err:", err)
+	}
+	body := req.Body
+	rt := &RetryTransport{MaxAttempts: 2}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal("RetryTransport.RoundTrip returned err:", err)
+	}
+	resp.Body.Close()
+
+	if req.Body != body {
+		t.Error("RetryTransport.RoundTrip replaced the body of the request")
+	}
+	if want := []string{"payload", "payload"}; !reflect.DeepEqual(bodies, want) {
+		t.Errorf("server received bodies %q, want %q", bodies, want)
+	}
+}
+
+func TestWithRetry_givesUp(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "something broke", 503)
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	c := NewClient(WithBaseURL(u), WithRetry(2, time.Millisecond))
+	_, _, err := c.Draws.Latest(Joker)
+	testErrorResponse(t, err, 503)
+	if got, want := atomic.LoadInt32(&calls), int32(2); got != want {
+		t.Errorf("server called %d times, want %d", got, want)
+	}
+}
+
+func TestWithRetry_notRetried(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "not found", 404)
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	c := NewClient(WithBaseURL(u), WithRetry(5, time.Millisecond))
+	_, _, err := c.Draws.Latest(Joker)
+	testErrorResponse(t, err, 404)
+	if got, want := atomic.LoadInt32(&calls), int32(1); got != want {
+		t.Errorf("server called %d times, want %d", got, want)
+	}
+}
+
+func TestWithRetry_connectionError(t *testing.T) {
+	var calls int32
+	rt := &RetryTransport{
+		Base: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, fmt.Errorf("connection reset")
+		}),
+		MaxAttempts:  3,
+		InitialDelay: time.Millisecond,
+	}
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	if _, err := rt.RoundTrip(req); err == nil {
+		t.Fatal("expected error")
+	}
+	if got, want := atomic.LoadInt32(&calls), int32(3); got != want {
+		t.Errorf("transport called %d times, want %d", got, want)
+	}
+}
+
+func TestWithRetry_contextDeadline(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "something broke", 500)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithRetry(10, time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+
+	start := time.Now()
+	_, err := c.Do(req.WithContext(ctx), nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("retries continued for %v after the context deadline", elapsed)
+	}
+	if got, want := atomic.LoadInt32(&calls), int32(1); got != want {
+		t.Errorf("server called %d times, want %d", got, want)
+	}
+}
+
+func TestWithRetry_leavesHTTPClientUntouched(t *testing.T) {
+	httpcl := &http.Client{}
+	c := NewClient(WithRetry(3, time.Millisecond), WithHTTPClient(httpcl))
+
+	if httpcl.Transport != nil {
+		t.Errorf("WithRetry changed the transport of the passed http.Client to %T", httpcl.Transport)
+	}
+	if _, ok := c.client.Transport.(*RetryTransport); !ok {
+		t.Errorf("client transport = %T, want *RetryTransport", c.client.Transport)
+	}
+	if http.DefaultClient.Transport != nil {
+		t.Errorf("WithRetry changed the transport of http.DefaultClient to %T", http.DefaultClient.Transport)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
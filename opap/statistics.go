@@ -0,0 +1,283 @@
+package opap
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrInsufficientData is returned by functions that compare a draw against a
+// history of draws when the history is too short for the comparison to mean
+// anything.
+var ErrInsufficientData = errors.New("insufficient draw history")
+
+// minHistory is the number of draws of history that HistoricalCompare needs
+// at least.
+const minHistory = 10
+
+// StatisticsService computes number statistics over a snapshot of draw
+// history. It is created with Client.Statistics and never changes after that,
+// so its methods are safe for concurrent use.
+//
+// The snapshot does not know which game its draws belong to, so every result
+// of a draw is counted, including joker and bonus numbers, and numbers that
+// never appear in the snapshot are not considered.
+type StatisticsService struct {
+	draws []Draw
+	freq  map[int]int
+	// ranked and coldest hold the numbers of freq from the most and the
+	// least frequent respectively, with ties in ascending order.
+	ranked  []int
+	coldest []int
+}
+
+// Statistics returns a StatisticsService over draws. The draws are copied so
+// later changes to draws do not affect the returned service.
+func (c *Client) Statistics(draws []Draw) *StatisticsService {
+	s := &StatisticsService{
+		draws: make([]Draw, len(draws)),
+	}
+	for i := range draws {
+		s.draws[i] = draws[i].Copy()
+	}
+	s.freq = NumberFrequency(draws)
+	for n := range s.freq {
+		s.ranked = append(s.ranked, n)
+	}
+	sort.Slice(s.ranked, func(i, j int) bool {
+		a, b := s.ranked[i], s.ranked[j]
+		if s.freq[a] != s.freq[b] {
+			return s.freq[a] > s.freq[b]
+		}
+		return a < b
+	})
+	s.coldest = make([]int, len(s.ranked))
+	copy(s.coldest, s.ranked)
+	sort.SliceStable(s.coldest, func(i, j int) bool {
+		return s.freq[s.coldest[i]] < s.freq[s.coldest[j]]
+	})
+	return s
+}
+
+// Frequency returns how many times each number appears in the results of the
+// draws. The returned map is a copy and may be modified.
+func (s *StatisticsService) Frequency() map[int]int {
+	m := make(map[int]int, len(s.freq))
+	for n, c := range s.freq {
+		m[n] = c
+	}
+	return m
+}
+
+// Hottest returns the n most frequent numbers, most frequent first. Numbers
+// that appear equally often are returned in ascending order. It returns all
+// the numbers if there are fewer than n.
+func (s *StatisticsService) Hottest(n int) []int {
+	if n > len(s.ranked) {
+		n = len(s.ranked)
+	}
+	if n <= 0 {
+		return nil
+	}
+	hot := make([]int, n)
+	copy(hot, s.ranked)
+	return hot
+}
+
+// Coldest returns the n least frequent numbers, least frequent first. Numbers
+// that appear equally often are returned in ascending order. It returns all
+// the numbers if there are fewer than n.
+func (s *StatisticsService) Coldest(n int) []int {
+	if n > len(s.ranked) {
+		n = len(s.ranked)
+	}
+	if n <= 0 {
+		return nil
+	}
+	cold := make([]int, n)
+	copy(cold, s.coldest)
+	return cold
+}
+
+// OverdueSince returns how many draws have taken place since number last
+// appeared in a draw numbered up to drawNo, counted as the difference of
+// their draw numbers. It returns -1 if number does not appear in any draw of
+// the snapshot numbered up to drawNo.
+func (s *StatisticsService) OverdueSince(number, drawNo int) int {
+	last := -1
+	for i := range s.draws {
+		d := &s.draws[i]
+		if d.DrawNo <= drawNo && d.DrawNo > last && d.Contains(number) {
+			last = d.DrawNo
+		}
+	}
+	if last < 0 {
+		return -1
+	}
+	return drawNo - last
+}
+
+// RollingFrequency returns how many times each number appears in the results
+// of every window of windowSize consecutive draws. Element i of the returned
+// slice holds the frequencies of draws[i:i+windowSize]. It returns nil if
+// windowSize is not positive or is greater than the number of draws.
+func RollingFrequency(draws []Draw, windowSize int) []map[int]int {
+	if windowSize <= 0 || windowSize > len(draws) {
+		return nil
+	}
+	windows := make([]map[int]int, 0, len(draws)-windowSize+1)
+	freq := make(map[int]int)
+	for i := range draws {
+		for _, n := range draws[i].Results {
+			freq[n]++
+		}
+		if i >= windowSize {
+			for _, n := range draws[i-windowSize].Results {
+				if freq[n]--; freq[n] == 0 {
+					delete(freq, n)
+				}
+			}
+		}
+		if i >= windowSize-1 {
+			w := make(map[int]int, len(freq))
+			for n, c := range freq {
+				w[n] = c
+			}
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
+
+// NumberFrequency returns how many times each number appears in the results
+// of draws.
+func NumberFrequency(draws []Draw) map[int]int {
+	freq := make(map[int]int)
+	for i := range draws {
+		for _, n := range draws[i].Results {
+			freq[n]++
+		}
+	}
+	return freq
+}
+
+// MostCommonResult returns the number that appears most often in the results
+// of history, as counted by NumberFrequency. If several numbers appear equally
+// often, the lowest of them is returned. It returns ErrEmptySlice if history
+// is empty, or an error if none of its draws have results.
+func MostCommonResult(history []Draw) (int, error) {
+	return commonResult(history, func(c, best int) bool { return c > best })
+}
+
+// LeastCommonResult is like MostCommonResult but returns the number that
+// appears least often. Numbers that never appear in history are not
+// considered.
+func LeastCommonResult(history []Draw) (int, error) {
+	return commonResult(history, func(c, best int) bool { return c < best })
+}
+
+// commonResult returns the lowest of the numbers of history whose count is
+// not beaten by any other, according to better.
+func commonResult(history []Draw, better func(c, best int) bool) (int, error) {
+	if len(history) == 0 {
+		return 0, ErrEmptySlice
+	}
+	freq := NumberFrequency(history)
+	if len(freq) == 0 {
+		return 0, errors.New("draws have no results")
+	}
+	var num, count int
+	first := true
+	for n, c := range freq {
+		if first || better(c, count) || (c == count && n < num) {
+			num, count, first = n, c, false
+		}
+	}
+	return num, nil
+}
+
+// HistoricalReport describes how a draw compares to a history of draws of the
+// same game, as computed by HistoricalCompare. Only the main results of the
+// draws are considered, that is the results without a joker or bonus number.
+type HistoricalReport struct {
+	// DrawSumPercentile is the percentage, from 0 to 100, of the draws of
+	// the history whose sum is lower than the sum of the draw.
+	DrawSumPercentile float64
+	// MostFrequentHits is how many of the results of the draw are among the
+	// numbers of the pool that appear most often in the history, taking as
+	// many numbers as a draw has, and LeastFrequentHits is the same for the
+	// numbers that appear least often, including numbers that never appear.
+	// Numbers that appear equally often are taken in ascending order.
+	MostFrequentHits  int
+	LeastFrequentHits int
+	// IsAboveMedianSum reports whether the sum of the draw is above the
+	// median of the sums of the history.
+	IsAboveMedianSum bool
+}
+
+// HistoricalCompare compares draw to history, which are draws of game g, for
+// example to see how this week's draw stands against all the draws so far.
+// It returns ErrUnknownGame if g is not one of the SupportedGames,
+// ErrNoBallPool if g does not draw balls, ErrInsufficientData if history has
+// fewer than 10 draws and ErrInsufficientResults if draw has no results.
+func HistoricalCompare(g Game, draw Draw, history []Draw) (HistoricalReport, error) {
+	info, err := InfoFor(g)
+	if err != nil {
+		return HistoricalReport{}, err
+	}
+	if info.PoolSize == 0 {
+		return HistoricalReport{}, ErrNoBallPool
+	}
+	if len(history) < minHistory {
+		return HistoricalReport{}, ErrInsufficientData
+	}
+	main := draw.mainResults(g)
+	if len(main) == 0 {
+		return HistoricalReport{}, ErrInsufficientResults
+	}
+
+	sums := make([]int, len(history))
+	freq := make(map[int]int)
+	for i := range history {
+		sums[i] = history[i].mainSum(g)
+		for _, n := range history[i].mainResults(g) {
+			freq[n]++
+		}
+	}
+	sort.Ints(sums)
+	sum := draw.mainSum(g)
+	median := float64(sums[len(sums)/2])
+	if len(sums)%2 == 0 {
+		median = float64(sums[len(sums)/2-1]+sums[len(sums)/2]) / 2
+	}
+
+	hottest := make([]int, info.PoolSize)
+	for i := range hottest {
+		hottest[i] = info.FirstBall + i
+	}
+	coldest := make([]int, len(hottest))
+	copy(coldest, hottest)
+	sort.SliceStable(hottest, func(i, j int) bool { return freq[hottest[i]] > freq[hottest[j]] })
+	sort.SliceStable(coldest, func(i, j int) bool { return freq[coldest[i]] < freq[coldest[j]] })
+
+	return HistoricalReport{
+		DrawSumPercentile: 100 * float64(sort.SearchInts(sums, sum)) / float64(len(sums)),
+		MostFrequentHits:  countHits(main, hottest[:info.DrawCount]),
+		LeastFrequentHits: countHits(main, coldest[:info.DrawCount]),
+		IsAboveMedianSum:  float64(sum) > median,
+	}, nil
+}
+
+// countHits returns how many of results are among numbers.
+func countHits(results, numbers []int) int {
+	set := make(map[int]bool, len(numbers))
+	for _, n := range numbers {
+		set[n] = true
+	}
+	hits := 0
+	for _, n := range results {
+		if set[n] {
+			hits++
+		}
+	}
+	return hits
+}
@@ -0,0 +1,232 @@
+package opap
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var statisticsDraws = []Draw{
+	{DrawNo: 1871, Results: []int{1, 2, 3}},
+	{DrawNo: 1872, Results: []int{2, 3, 4}},
+	{DrawNo: 1873, Results: []int{3, 4, 5}},
+}
+
+func TestStatisticsService_Frequency(t *testing.T) {
+	s := NewClient().Statistics(statisticsDraws)
+
+	want := map[int]int{1: 1, 2: 2, 3: 3, 4: 2, 5: 1}
+	if got := s.Frequency(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Frequency() \nhave: %v\nwant: %v", got, want)
+	}
+
+	s.Frequency()[3] = 100
+	if got := s.Frequency()[3]; got != 3 {
+		t.Errorf("Frequency()[3] = %d after modifying a returned map, want 3", got)
+	}
+}
+
+func TestStatisticsService_snapshot(t *testing.T) {
+	draws := []Draw{{DrawNo: 1, Results: []int{7}}}
+	s := NewClient().Statistics(draws)
+	draws[0].Results[0] = 8
+	draws[0].DrawNo = 2
+
+	if got, want := s.Frequency(), map[int]int{7: 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Frequency() after modifying draws \nhave: %v\nwant: %v", got, want)
+	}
+	if got, want := s.OverdueSince(7, 1), 0; got != want {
+		t.Errorf("OverdueSince(7, 1) after modifying draws = %d, want %d", got, want)
+	}
+}
+
+func TestStatisticsService_HottestColdest(t *testing.T) {
+	s := NewClient().Statistics(statisticsDraws)
+
+	tests := []struct {
+		n                int
+		hottest, coldest []int
+	}{
+		{0, nil, nil},
+		{1, []int{3}, []int{1}},
+		{2, []int{3, 2}, []int{1, 5}},
+		{3, []int{3, 2, 4}, []int{1, 5, 2}},
+		{10, []int{3, 2, 4, 1, 5}, []int{1, 5, 2, 4, 3}},
+	}
+	for _, tt := range tests {
+		if got := s.Hottest(tt.n); !reflect.DeepEqual(got, tt.hottest) {
+			t.Errorf("Hottest(%d) = %v, want %v", tt.n, got, tt.hottest)
+		}
+		if got := s.Coldest(tt.n); !reflect.DeepEqual(got, tt.coldest) {
+			t.Errorf("Coldest(%d) = %v, want %v", tt.n, got, tt.coldest)
+		}
+	}
+}
+
+func TestStatisticsService_OverdueSince(t *testing.T) {
+	s := NewClient().Statistics(statisticsDraws)
+
+	tests := []struct {
+		number, drawNo, want int
+	}{
+		{1, 1873, 2},
+		{2, 1873, 1},
+		{3, 1873, 0},
+		{5, 1880, 7},
+		{5, 1872, -1},
+		{9, 1873, -1},
+	}
+	for _, tt := range tests {
+		if got := s.OverdueSince(tt.number, tt.drawNo); got != tt.want {
+			t.Errorf("OverdueSince(%d, %d) = %d, want %d", tt.number, tt.drawNo, got, tt.want)
+		}
+	}
+}
+
+func TestStatisticsService_concurrentReads(t *testing.T) {
+	s := NewClient().Statistics(statisticsDraws)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Frequency()
+			s.Hottest(2)
+			s.Coldest(2)
+			s.OverdueSince(1, 1873)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRollingFrequency(t *testing.T) {
+	want := []map[int]int{
+		{1: 1, 2: 2, 3: 2, 4: 1},
+		{2: 1, 3: 2, 4: 2, 5: 1},
+	}
+	got := RollingFrequency(statisticsDraws, 2)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RollingFrequency(draws, 2) \nhave: %v\nwant: %v", got, want)
+	}
+
+	const resultsPerDraw = 3
+	for windowSize := 1; windowSize <= len(statisticsDraws); windowSize++ {
+		windows := RollingFrequency(statisticsDraws, windowSize)
+		if got, want := len(windows), len(statisticsDraws)-windowSize+1; got != want {
+			t.Errorf("RollingFrequency(draws, %d) returned %d windows, want %d", windowSize, got, want)
+		}
+		for i, w := range windows {
+			sum := 0
+			for _, c := range w {
+				sum += c
+			}
+			if want := windowSize * resultsPerDraw; sum != want {
+				t.Errorf("RollingFrequency(draws, %d)[%d] frequencies sum to %d, want %d", windowSize, i, sum, want)
+			}
+		}
+	}
+
+	for _, windowSize := range []int{0, -1, len(statisticsDraws) + 1} {
+		if got := RollingFrequency(statisticsDraws, windowSize); got != nil {
+			t.Errorf("RollingFrequency(draws, %d) = %v, want nil", windowSize, got)
+		}
+	}
+}
+
+func TestNumberFrequency(t *testing.T) {
+	want := map[int]int{1: 1, 2: 2, 3: 3, 4: 2, 5: 1}
+	if got := NumberFrequency(statisticsDraws); !reflect.DeepEqual(got, want) {
+		t.Errorf("NumberFrequency \nhave: %v\nwant: %v", got, want)
+	}
+}
+
+func TestMostLeastCommonResult(t *testing.T) {
+	most, err := MostCommonResult(statisticsDraws)
+	if err != nil {
+		t.Fatal("MostCommonResult returned err:", err)
+	}
+	if want := 3; most != want {
+		t.Errorf("MostCommonResult = %d, want %d", most, want)
+	}
+	// 1 and 5 appear once each, so the lower one wins the tie.
+	least, err := LeastCommonResult(statisticsDraws)
+	if err != nil {
+		t.Fatal("LeastCommonResult returned err:", err)
+	}
+	if want := 1; least != want {
+		t.Errorf("LeastCommonResult = %d, want %d", least, want)
+	}
+	// 2 and 4 appear twice each, so the lower one wins the tie.
+	tied := []Draw{{Results: []int{4, 2}}, {Results: []int{2, 4, 7}}}
+	if got, _ := MostCommonResult(tied); got != 2 {
+		t.Errorf("MostCommonResult with a tie = %d, want 2", got)
+	}
+
+	for _, f := range []func([]Draw) (int, error){MostCommonResult, LeastCommonResult} {
+		if _, err := f(nil); err != ErrEmptySlice {
+			t.Errorf("err = %v, want %v", err, ErrEmptySlice)
+		}
+		if _, err := f([]Draw{{DrawNo: 1}}); err == nil {
+			t.Error("draws without results expected error")
+		}
+	}
+}
+
+func TestHistoricalCompare(t *testing.T) {
+	// 1 to 4 are drawn every time and 5 to 14 once each, so the sums of the
+	// history range from 15 to 24 with a median of 19.5.
+	var history []Draw
+	for i := 0; i < 10; i++ {
+		history = append(history, Draw{DrawNo: i + 1, Results: []int{1, 2, 3, 4, 5 + i}})
+	}
+
+	tests := []struct {
+		draw Draw
+		want HistoricalReport
+	}{
+		{
+			Draw{Results: []int{1, 2, 15, 16, 30}},
+			HistoricalReport{DrawSumPercentile: 100, MostFrequentHits: 2, LeastFrequentHits: 2, IsAboveMedianSum: true},
+		},
+		{
+			Draw{Results: []int{1, 2, 3, 4, 5}},
+			HistoricalReport{DrawSumPercentile: 0, MostFrequentHits: 5, LeastFrequentHits: 0, IsAboveMedianSum: false},
+		},
+		{
+			Draw{Results: []int{1, 2, 3, 4, 10}},
+			HistoricalReport{DrawSumPercentile: 50, MostFrequentHits: 4, LeastFrequentHits: 0, IsAboveMedianSum: true},
+		},
+	}
+	for _, tt := range tests {
+		got, err := HistoricalCompare(Extra5, tt.draw, history)
+		if err != nil {
+			t.Errorf("HistoricalCompare(%q, %v) returned err: %v", Extra5, tt.draw.Results, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("HistoricalCompare(%q, %v) \nhave: %#v\nwant: %#v", Extra5, tt.draw.Results, got, tt.want)
+		}
+	}
+}
+
+func TestHistoricalCompare_error(t *testing.T) {
+	history := make([]Draw, 10)
+	draw := Draw{Results: []int{1, 2, 3, 4, 5}}
+	tests := []struct {
+		game    Game
+		draw    Draw
+		history []Draw
+		err     error
+	}{
+		{Game("foo"), draw, history, ErrUnknownGame},
+		{Penalties, draw, history, ErrNoBallPool},
+		{Extra5, draw, history[:9], ErrInsufficientData},
+		{Extra5, Draw{}, history, ErrInsufficientResults},
+	}
+	for _, tt := range tests {
+		if _, err := HistoricalCompare(tt.game, tt.draw, tt.history); err != tt.err {
+			t.Errorf("HistoricalCompare(%q) with %d draws err = %v, want %v", tt.game, len(tt.history), err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package opap
+
+import "fmt"
+
+// Ticket holds the numbers played on a ticket. Bonus is the joker number
+// played on a Joker ticket and is ignored for other games.
+type Ticket struct {
+	Numbers []int
+	Bonus   int
+}
+
+// prizeTier is a winning combination of a game: how many main numbers are
+// matched and whether the bonus number is matched.
+type prizeTier struct {
+	main  int
+	bonus bool
+}
+
+// prizeTiers lists the winning combinations of the games that CheckDraw
+// supports, from the first tier down.
+var prizeTiers = map[Game][]prizeTier{
+	Joker: {
+		{5, true}, {5, false}, {4, true}, {4, false},
+		{3, true}, {3, false}, {2, true}, {1, true},
+	},
+	Lotto: {
+		{6, false}, {5, true}, {5, false}, {4, false}, {3, false},
+	},
+}
+
+// CheckDraw checks the ticket against draw d of game g. It returns the prize
+// tier that the ticket wins, starting from 1 for the top prize, or 0 if it
+// wins no prize, along with the ticket numbers that appear in the main
+// results of the draw. For Joker the bonus number of the ticket must match
+// the joker number of the draw, while for Lotto one of the ticket numbers must
+// match the bonus number of the draw. Only Joker and Lotto are supported. It
+// returns an error if the ticket or the draw are not valid for g.
+func (t *Ticket) CheckDraw(g Game, d Draw) (tier int, matched []int, err error) {
+	tiers, ok := prizeTiers[g]
+	if !ok {
+		return 0, nil, fmt.Errorf("no prize tiers for game %s", string(g))
+	}
+	info, err := InfoFor(g)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := t.validate(info); err != nil {
+		return 0, nil, err
+	}
+	if err := d.AssertResultsLength(info.DrawCount + info.BonusCount); err != nil {
+		return 0, nil, err
+	}
+
+	main, bonus := d.Results[:info.DrawCount], d.Results[info.DrawCount]
+	matchedBonus := false
+	for _, n := range t.Numbers {
+		for _, r := range main {
+			if n == r {
+				matched = append(matched, n)
+				break
+			}
+		}
+		if g == Lotto && n == bonus {
+			matchedBonus = true
+		}
+	}
+	if g == Joker {
+		matchedBonus = t.Bonus == bonus
+	}
+
+	for i, pt := range tiers {
+		if len(matched) == pt.main && (matchedBonus || !pt.bonus) {
+			return i + 1, matched, nil
+		}
+	}
+	return 0, matched, nil
+}
+
+// validate returns an error if the ticket does not play as many distinct
+// numbers as game info draws, out of its pool, or if a Joker ticket does not
+// play a bonus number out of the joker pool.
+func (t *Ticket) validate(info GameInfo) error {
+	if len(t.Numbers) != info.DrawCount {
+		return fmt.Errorf("ticket has %d numbers, want %d", len(t.Numbers), info.DrawCount)
+	}
+	seen := make(map[int]struct{}, len(t.Numbers))
+	for _, n := range t.Numbers {
+		if n < 1 || n > info.PoolSize {
+			return fmt.Errorf("ticket number %d is out of range 1 to %d", n, info.PoolSize)
+		}
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("ticket number %d is played twice", n)
+		}
+		seen[n] = struct{}{}
+	}
+	if info.Name == Joker && (t.Bonus < 1 || t.Bonus > info.BonusPoolSize) {
+		return fmt.Errorf("ticket joker number %d is out of range 1 to %d", t.Bonus, info.BonusPoolSize)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package opap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTicket_CheckDraw_joker(t *testing.T) {
+	d := Draw{DrawNo: 1873, Results: []int{40, 13, 1, 24, 15, 8}}
+
+	tests := []struct {
+		numbers     []int
+		bonus       int
+		wantTier    int
+		wantMatched []int
+	}{
+		{[]int{1, 13, 15, 24, 40}, 8, 1, []int{1, 13, 15, 24, 40}},
+		{[]int{1, 13, 15, 24, 40}, 9, 2, []int{1, 13, 15, 24, 40}},
+		{[]int{1, 13, 15, 24, 41}, 8, 3, []int{1, 13, 15, 24}},
+		{[]int{1, 13, 15, 24, 41}, 9, 4, []int{1, 13, 15, 24}},
+		{[]int{1, 13, 15, 42, 41}, 8, 5, []int{1, 13, 15}},
+		{[]int{1, 13, 15, 42, 41}, 9, 6, []int{1, 13, 15}},
+		{[]int{1, 13, 43, 42, 41}, 8, 7, []int{1, 13}},
+		{[]int{1, 44, 43, 42, 41}, 8, 8, []int{1}},
+		{[]int{1, 13, 43, 42, 41}, 9, 0, []int{1, 13}},
+		{[]int{45, 44, 43, 42, 41}, 8, 0, nil},
+		// The joker number of the draw is not one of its main numbers.
+		{[]int{8, 44, 43, 42, 41}, 9, 0, nil},
+	}
+	for _, tt := range tests {
+		ticket := &Ticket{Numbers: tt.numbers, Bonus: tt.bonus}
+		tier, matched, err := ticket.CheckDraw(Joker, d)
+		if err != nil {
+			t.Errorf("Ticket%v.CheckDraw(joker) returned err: %v", *ticket, err)
+			continue
+		}
+		if tier != tt.wantTier || !reflect.DeepEqual(matched, tt.wantMatched) {
+			t.Errorf("Ticket%v.CheckDraw(joker) = %d, %v, want %d, %v", *ticket, tier, matched, tt.wantTier, tt.wantMatched)
+		}
+	}
+}
+
+func TestTicket_CheckDraw_lotto(t *testing.T) {
+	d := Draw{DrawNo: 1780, Results: []int{4, 9, 17, 23, 31, 44, 12}}
+
+	tests := []struct {
+		numbers     []int
+		wantTier    int
+		wantMatched []int
+	}{
+		{[]int{4, 9, 17, 23, 31, 44}, 1, []int{4, 9, 17, 23, 31, 44}},
+		{[]int{4, 9, 17, 23, 31, 12}, 2, []int{4, 9, 17, 23, 31}},
+		{[]int{4, 9, 17, 23, 31, 1}, 3, []int{4, 9, 17, 23, 31}},
+		{[]int{4, 9, 17, 23, 12, 1}, 4, []int{4, 9, 17, 23}},
+		{[]int{4, 9, 17, 2, 12, 1}, 5, []int{4, 9, 17}},
+		{[]int{4, 9, 3, 2, 12, 1}, 0, []int{4, 9}},
+	}
+	for _, tt := range tests {
+		ticket := &Ticket{Numbers: tt.numbers}
+		tier, matched, err := ticket.CheckDraw(Lotto, d)
+		if err != nil {
+			t.Errorf("Ticket%v.CheckDraw(lotto) returned err: %v", *ticket, err)
+			continue
+		}
+		if tier != tt.wantTier || !reflect.DeepEqual(matched, tt.wantMatched) {
+			t.Errorf("Ticket%v.CheckDraw(lotto) = %d, %v, want %d, %v", *ticket, tier, matched, tt.wantTier, tt.wantMatched)
+		}
+	}
+}
+
+func TestTicket_CheckDraw_errors(t *testing.T) {
+	joker := Draw{Results: []int{40, 13, 1, 24, 15, 8}}
+	tests := []struct {
+		game   Game
+		ticket Ticket
+		draw   Draw
+	}{
+		{Kino, Ticket{Numbers: []int{1}}, Draw{Results: []int{1}}},
+		{Joker, Ticket{Numbers: []int{1, 2, 3, 4}, Bonus: 1}, joker},
+		{Joker, Ticket{Numbers: []int{1, 2, 3, 4, 46}, Bonus: 1}, joker},
+		{Joker, Ticket{Numbers: []int{1, 2, 3, 4, 4}, Bonus: 1}, joker},
+		{Joker, Ticket{Numbers: []int{1, 2, 3, 4, 5}, Bonus: 21}, joker},
+		{Joker, Ticket{Numbers: []int{1, 2, 3, 4, 5}, Bonus: 1}, Draw{Results: []int{40, 13, 1, 24, 15}}},
+	}
+	for _, tt := range tests {
+		if _, _, err := tt.ticket.CheckDraw(tt.game, tt.draw); err == nil {
+			t.Errorf("Ticket%v.CheckDraw(%s, %v) expected error", tt.ticket, string(tt.game), tt.draw.Results)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package opap
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Watch polls the latest draw of game g every interval and sends each draw
+// whose DrawNo differs from the previously seen one on the returned draws
+// channel, starting with the draw found by the first poll, which is made
+// right away. Errors are sent on the returned errors channel and do not stop
+// the polling, so a transient error only skips a poll. Both channels are
+// closed once ctx is done. The caller must keep receiving from both channels
+// until then, since polling waits for each draw or error to be received. Each
+// poll request is cancelled when ctx is done. If interval is not positive, an
+// error is sent on the errors channel and both channels are closed without
+// polling.
+func (s *drawsService) Watch(ctx context.Context, g Game, interval time.Duration) (<-chan *Draw, <-chan error) {
+	if interval <= 0 {
+		draws := make(chan *Draw)
+		close(draws)
+		return draws, invalidInterval(interval)
+	}
+	draws := make(chan *Draw)
+	errs := make(chan error)
+	go func() {
+		defer close(draws)
+		defer close(errs)
+		seen := false
+		var last int
+		poll(ctx, interval, func() {
+			d, _, err := s.latest(ctx, g)
+			if err != nil {
+				select {
+				case errs <- err:
+				case <-ctx.Done():
+				}
+				return
+			}
+			if seen && d.DrawNo == last {
+				return
+			}
+			seen, last = true, d.DrawNo
+			select {
+			case draws <- d:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return draws, errs
+}
+
+// WatchPropo is like Watch for the Propo games.
+func (s *drawsService) WatchPropo(ctx context.Context, g PropoGame, interval time.Duration) (<-chan *PropoDraw, <-chan error) {
+	if interval <= 0 {
+		draws := make(chan *PropoDraw)
+		close(draws)
+		return draws, invalidInterval(interval)
+	}
+	draws := make(chan *PropoDraw)
+	errs := make(chan error)
+	go func() {
+		defer close(draws)
+		defer close(errs)
+		seen := false
+		var last int
+		poll(ctx, interval, func() {
+			d, _, err := s.propoLatest(ctx, g)
+			if err != nil {
+				select {
+				case errs <- err:
+				case <-ctx.Done():
+				}
+				return
+			}
+			if seen && d.DrawNo == last {
+				return
+			}
+			seen, last = true, d.DrawNo
+			select {
+			case draws <- d:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return draws, errs
+}
+
+// WatchAll is like Watch for all of the SupportedGames at once. It watches
+// each game separately and sends the new draws of all the games, bundled with
+// their game, on the returned draws channel. The errors of each game are sent
+// on the returned errors channel as a GameError that names the game. Both
+// channels are closed once ctx is done and every game has stopped polling.
+func (s *drawsService) WatchAll(ctx context.Context, interval time.Duration) (<-chan GameDraw, <-chan error) {
+	if interval <= 0 {
+		draws := make(chan GameDraw)
+		close(draws)
+		return draws, invalidInterval(interval)
+	}
+	out := make(chan GameDraw)
+	errs := make(chan error)
+	var wg sync.WaitGroup
+	for _, g := range AllGames() {
+		wg.Add(1)
+		go func(g Game) {
+			defer wg.Done()
+			draws, gameErrs := s.Watch(ctx, g, interval)
+			for draws != nil || gameErrs != nil {
+				select {
+				case d, ok := <-draws:
+					if !ok {
+						draws = nil
+						continue
+					}
+					select {
+					case out <- d.WithGame(g):
+					case <-ctx.Done():
+					}
+				case err, ok := <-gameErrs:
+					if !ok {
+						gameErrs = nil
+						continue
+					}
+					select {
+					case errs <- GameError{Game: string(g), Err: err}:
+					case <-ctx.Done():
+					}
+				}
+			}
+		}(g)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+		close(errs)
+	}()
+	return out, errs
+}
+
+// invalidInterval returns a closed errors channel that holds the error of an
+// invalid polling interval.
+func invalidInterval(interval time.Duration) <-chan error {
+	errs := make(chan error, 1)
+	errs <- fmt.Errorf("invalid polling interval %v", interval)
+	close(errs)
+	return errs
+}
+
+// poll calls fn right away and then every interval until ctx is done.
+func poll(ctx context.Context, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		fn()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
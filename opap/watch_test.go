@@ -0,0 +1,251 @@
+package opap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDrawService_Watch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// The draw number changes on the third and fifth poll while the fourth
+	// poll fails.
+	var calls int32
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		switch n := atomic.AddInt32(&calls, 1); {
+		case n <= 2:
+			fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+		case n == 3:
+			fmt.Fprint(w, `{"draw":{"drawNo":1874}}`)
+		case n == 4:
+			http.Error(w, "something broke", 500)
+		default:
+			fmt.Fprint(w, `{"draw":{"drawNo":1875}}`)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	draws, errs := client.Draws.Watch(ctx, Joker, time.Millisecond)
+
+	for _, want := range []int{1873, 1874} {
+		d := <-draws
+		if got := d.DrawNo; got != want {
+			t.Errorf("client.Draws.Watch sent draw %d, want %d", got, want)
+		}
+	}
+	testErrorResponse(t, <-errs, 500)
+	if got, want := (<-draws).DrawNo, 1875; got != want {
+		t.Errorf("client.Draws.Watch sent draw %d, want %d", got, want)
+	}
+
+	cancel()
+	for range draws {
+	}
+	for range errs {
+	}
+}
+
+func TestDrawService_Watch_cancelWhileSending(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	draws, errs := client.Draws.Watch(ctx, Joker, time.Hour)
+	// Nothing receives the first draw, so the watcher must give up sending
+	// it once ctx is cancelled.
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-waitClosed(draws, errs):
+	case <-time.After(time.Second):
+		t.Fatal("client.Draws.Watch did not close its channels after ctx was cancelled")
+	}
+}
+
+func TestDrawService_WatchPropo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/proposat/last.json", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			fmt.Fprint(w, `{"draw":{"drawNo":201751}}`)
+			return
+		}
+		fmt.Fprint(w, `{"draw":{"drawNo":201752}}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	draws, errs := client.Draws.WatchPropo(ctx, PropoSat, time.Millisecond)
+	for _, want := range []int{201751, 201752} {
+		if got := (<-draws).DrawNo; got != want {
+			t.Errorf("client.Draws.WatchPropo sent draw %d, want %d", got, want)
+		}
+	}
+
+	cancel()
+	select {
+	case <-waitClosed(draws, errs):
+	case <-time.After(time.Second):
+		t.Fatal("client.Draws.WatchPropo did not close its channels after ctx was cancelled")
+	}
+}
+
+func TestDrawService_WatchAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + defaultDrawsEndpoint + "/joker/last.json":
+			fmt.Fprint(w, `{"draw":{"drawNo":1873}}`)
+		case "/" + defaultDrawsEndpoint + "/lotto/last.json":
+			fmt.Fprint(w, `{"draw":{"drawNo":1726}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	draws, errs := client.Draws.WatchAll(ctx, time.Hour)
+
+	// Each game is polled once, so Joker and Lotto send a draw each and the
+	// rest of the games send an error each.
+	gotDraws := make(map[Game]int)
+	gotErrs := make(map[string]bool)
+	for len(gotDraws)+len(gotErrs) < len(SupportedGames) {
+		select {
+		case gd := <-draws:
+			gotDraws[gd.Game] = gd.DrawNo
+		case err := <-errs:
+			ge, ok := err.(GameError)
+			if !ok {
+				t.Fatalf("client.Draws.WatchAll sent error %T, want GameError", err)
+			}
+			if !IsNotFound(ge) {
+				t.Errorf("client.Draws.WatchAll sent error %v, want not found", ge)
+			}
+			gotErrs[ge.Game] = true
+		case <-time.After(time.Second):
+			t.Fatal("client.Draws.WatchAll did not poll every game")
+		}
+	}
+	if want := map[Game]int{Joker: 1873, Lotto: 1726}; !reflect.DeepEqual(gotDraws, want) {
+		t.Errorf("client.Draws.WatchAll draws \nhave: %v\nwant: %v", gotDraws, want)
+	}
+	if gotErrs["joker"] || gotErrs["lotto"] {
+		t.Errorf("client.Draws.WatchAll sent errors for joker or lotto: %v", gotErrs)
+	}
+
+	cancel()
+	select {
+	case <-waitClosedGameDraws(draws, errs):
+	case <-time.After(time.Second):
+		t.Fatal("client.Draws.WatchAll did not close its channels after ctx was cancelled")
+	}
+}
+
+func TestDrawService_WatchAll_invalidInterval(t *testing.T) {
+	draws, errs := NewClient().Draws.WatchAll(context.Background(), 0)
+	if err := <-errs; err == nil {
+		t.Error("client.Draws.WatchAll with zero interval expected error")
+	}
+	select {
+	case <-waitClosedGameDraws(draws, errs):
+	case <-time.After(time.Second):
+		t.Fatal("client.Draws.WatchAll did not close its channels after an invalid interval")
+	}
+}
+
+// waitClosedGameDraws is like waitClosed for the channels of WatchAll.
+func waitClosedGameDraws(draws <-chan GameDraw, errs <-chan error) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range draws {
+		}
+		for range errs {
+		}
+	}()
+	return done
+}
+
+// waitClosed returns a channel that is closed once draws and errs are both
+// closed, discarding any values received from them until then.
+func waitClosed(draws interface{}, errs <-chan error) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		switch c := draws.(type) {
+		case <-chan *Draw:
+			for range c {
+			}
+		case <-chan *PropoDraw:
+			for range c {
+			}
+		}
+		for range errs {
+		}
+	}()
+	return done
+}
+
+func TestDrawService_Watch_invalidInterval(t *testing.T) {
+	setup()
+	defer teardown()
+
+	draws, errs := client.Draws.Watch(context.Background(), Joker, 0)
+	if err := <-errs; err == nil {
+		t.Error("client.Draws.Watch with zero interval expected error")
+	}
+	select {
+	case <-waitClosed(draws, errs):
+	case <-time.After(time.Second):
+		t.Fatal("client.Draws.Watch did not close its channels after an invalid interval")
+	}
+
+	propoDraws, errs := client.Draws.WatchPropo(context.Background(), PropoSat, -time.Second)
+	if err := <-errs; err == nil {
+		t.Error("client.Draws.WatchPropo with negative interval expected error")
+	}
+	select {
+	case <-waitClosed(propoDraws, errs):
+	case <-time.After(time.Second):
+		t.Fatal("client.Draws.WatchPropo did not close its channels after an invalid interval")
+	}
+}
+
+func TestDrawService_Watch_cancelWhileRequesting(t *testing.T) {
+	setup()
+	defer teardown()
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+	mux.HandleFunc("/"+defaultDrawsEndpoint+"/joker/last.json", func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	draws, errs := client.Draws.Watch(ctx, Joker, time.Hour)
+	// The first poll is still waiting for its response, so the watcher must
+	// cancel the request once ctx is cancelled.
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-waitClosed(draws, errs):
+	case <-time.After(time.Second):
+		t.Fatal("client.Draws.Watch did not close its channels after ctx was cancelled")
+	}
+}